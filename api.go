@@ -0,0 +1,152 @@
+package wukong_go_sdk
+
+import "context"
+
+// 以下接口与各个 Service 的方法一一对应，便于在单元测试中替换为 wukongmock 中的假实现，
+// 或者包装单个服务实现缓存、追踪等装饰器
+
+// RouteAPI 路由相关接口，由 *RouteService 实现
+type RouteAPI interface {
+	GetIMAddress(ctx context.Context, req *RouteAddressRequest, opts ...CallOption) (*RouteAddress, error)
+	BatchGetIMAddress(ctx context.Context, req *BatchRouteAddressRequest, opts ...CallOption) ([]BatchRouteAddress, error)
+}
+
+// MessageAPI 消息相关接口，由 *MessageService 实现
+// 只包含 REST 接口；IterateHistory、History、SendMany、Fanout、BatchGetMaxMessageSeq 等客户端组合逻辑
+// 以同名的包级函数提供，接收任意 MessageAPI，因此替换为假实现时也会走真实的分页与分批逻辑
+type MessageAPI interface {
+	SendMessage(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendMessageResponse, error)
+	BatchSendMessage(ctx context.Context, req *BatchSendMessageRequest, opts ...CallOption) ([]BatchSendMessageResponseItem, error)
+	MessageSync(ctx context.Context, req *MessageSyncRequest, opts ...CallOption) ([]Message, error)
+	GetMaxMessageSeq(ctx context.Context, req *MaxMessageSeqRequest, opts ...CallOption) (*MaxMessageSeqResponse, error)
+	UserSearch(ctx context.Context, req *UserSearchRequest, opts ...CallOption) (*UserSearchResponse, error)
+	BatchSearch(ctx context.Context, req *BatchSearchRequest, opts ...CallOption) ([]Message, error)
+	SingleSearch(ctx context.Context, req *SingleSearchRequest, opts ...CallOption) (*Message, error)
+}
+
+// ChannelAPI 频道相关接口，由 *ChannelService 实现
+type ChannelAPI interface {
+	Create(ctx context.Context, req *CreateChannelRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	UpdateInfo(ctx context.Context, req *UpdateInfoRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	AddSubscribers(ctx context.Context, req *AddSubscribersRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	RemoveSubscribers(ctx context.Context, req *RemoveSubscribersRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	Delete(ctx context.Context, req *DeleteChannelRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	AddBlacklist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	SetBlacklist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	RemoveBlacklist(ctx context.Context, req *RemoveBlacklistRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	AddWhitelist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	SetWhitelist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	RemoveWhitelist(ctx context.Context, req *RemoveWhitelistRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	GetWhitelist(ctx context.Context, req *GetWhitelistRequest, opts ...CallOption) ([]string, error)
+	SetTmpSubscriber(ctx context.Context, req *SetTmpSubscriberRequest, opts ...CallOption) (*CreateChannelResponse, error)
+}
+
+// UserAPI 用户相关接口，由 *UserService 实现
+type UserAPI interface {
+	UpdateToken(ctx context.Context, req *UpdateUserTokenRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	DeviceQuit(ctx context.Context, req *DeviceQuitRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	OnlineStatus(ctx context.Context, req *OnlineStatusRequest, opts ...CallOption) ([]UserOnlineStatus, error)
+	SystemUIDs(ctx context.Context, opts ...CallOption) ([]string, error)
+	AddSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	RemoveSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest, opts ...CallOption) (*CreateChannelResponse, error)
+}
+
+// ConversationAPI 会话相关接口，由 *ConversationService 实现
+type ConversationAPI interface {
+	Sync(ctx context.Context, req *ConversationSyncRequest, opts ...CallOption) ([]Conversation, error)
+	ClearUnread(ctx context.Context, req *ConversationClearUnreadRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	SetUnread(ctx context.Context, req *ConversationSetUnreadRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	Delete(ctx context.Context, req *ConversationDeleteRequest, opts ...CallOption) (*CreateChannelResponse, error)
+}
+
+// ConnectionAPI 连接相关接口，由 *ConnectionService 实现
+type ConnectionAPI interface {
+	Remove(ctx context.Context, req *ConnectionRequest, opts ...CallOption) (*CreateChannelResponse, error)
+	Kick(ctx context.Context, req *ConnectionRequest, opts ...CallOption) (*CreateChannelResponse, error)
+}
+
+// EventAPI 事件相关接口，由 *EventService 实现
+type EventAPI interface {
+	Send(ctx context.Context, req *EventSendRequest, opts ...CallOption) (*CreateChannelResponse, error)
+}
+
+// ManagerAPI 管理员相关接口，由 *ManagerService 实现
+type ManagerAPI interface {
+	Login(ctx context.Context, req *ManagerLoginRequest, opts ...CallOption) (*ManagerLoginResponse, error)
+}
+
+// SystemAPI 系统相关接口，由 *SystemService 实现
+type SystemAPI interface {
+	Health(ctx context.Context, opts ...CallOption) (*HealthStatus, error)
+}
+
+// API 聚合了所有服务接口，由 *Client 实现
+type API interface {
+	RouteAPI() RouteAPI
+	MessageAPI() MessageAPI
+	ChannelAPI() ChannelAPI
+	UserAPI() UserAPI
+	ConversationAPI() ConversationAPI
+	ConnectionAPI() ConnectionAPI
+	EventAPI() EventAPI
+	ManagerAPI() ManagerAPI
+	SystemAPI() SystemAPI
+}
+
+var (
+	_ RouteAPI        = (*RouteService)(nil)
+	_ MessageAPI      = (*MessageService)(nil)
+	_ ChannelAPI      = (*ChannelService)(nil)
+	_ UserAPI         = (*UserService)(nil)
+	_ ConversationAPI = (*ConversationService)(nil)
+	_ ConnectionAPI   = (*ConnectionService)(nil)
+	_ EventAPI        = (*EventService)(nil)
+	_ ManagerAPI      = (*ManagerService)(nil)
+	_ SystemAPI       = (*SystemService)(nil)
+	_ API             = (*Client)(nil)
+)
+
+// RouteAPI 以接口形式返回 Client.Route
+func (c *Client) RouteAPI() RouteAPI {
+	return c.Route
+}
+
+// MessageAPI 以接口形式返回 Client.Message
+func (c *Client) MessageAPI() MessageAPI {
+	return c.Message
+}
+
+// ChannelAPI 以接口形式返回 Client.Channel
+func (c *Client) ChannelAPI() ChannelAPI {
+	return c.Channel
+}
+
+// UserAPI 以接口形式返回 Client.User
+func (c *Client) UserAPI() UserAPI {
+	return c.User
+}
+
+// ConversationAPI 以接口形式返回 Client.Conversation
+func (c *Client) ConversationAPI() ConversationAPI {
+	return c.Conversation
+}
+
+// ConnectionAPI 以接口形式返回 Client.Connection
+func (c *Client) ConnectionAPI() ConnectionAPI {
+	return c.Connection
+}
+
+// EventAPI 以接口形式返回 Client.Event
+func (c *Client) EventAPI() EventAPI {
+	return c.Event
+}
+
+// ManagerAPI 以接口形式返回 Client.Manager
+func (c *Client) ManagerAPI() ManagerAPI {
+	return c.Manager
+}
+
+// SystemAPI 以接口形式返回 Client.System
+func (c *Client) SystemAPI() SystemAPI {
+	return c.System
+}
@@ -2,7 +2,6 @@ package wukong_go_sdk
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
@@ -27,13 +26,13 @@ type CreateChannelResponse struct {
 
 // Create 创建频道
 // POST /channel
-func (s *ChannelService) Create(ctx context.Context, req *CreateChannelRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) Create(ctx context.Context, req *CreateChannelRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel", req, &respBody)
+	err := s.client.do(ctx, "channel.Create", http.MethodPost, "/channel", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.Create", err)
 	}
@@ -50,13 +49,13 @@ type UpdateInfoRequest struct {
 
 // UpdateInfo 更新频道信息
 // POST /channel/info
-func (s *ChannelService) UpdateInfo(ctx context.Context, req *UpdateInfoRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) UpdateInfo(ctx context.Context, req *UpdateInfoRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/info", req, &respBody)
+	err := s.client.do(ctx, "channel.UpdateInfo", http.MethodPost, "/channel/info", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.UpdateInfo", err)
 	}
@@ -74,13 +73,13 @@ type AddSubscribersRequest struct {
 
 // AddSubscribers 添加频道订阅者
 // POST /channel/subscriber_add
-func (s *ChannelService) AddSubscribers(ctx context.Context, req *AddSubscribersRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) AddSubscribers(ctx context.Context, req *AddSubscribersRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/subscriber_add", req, &respBody)
+	err := s.client.do(ctx, "channel.AddSubscribers", http.MethodPost, "/channel/subscriber_add", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.AddSubscribers", err)
 	}
@@ -97,13 +96,13 @@ type RemoveSubscribersRequest struct {
 
 // RemoveSubscribers 移除频道订阅者
 // POST /channel/subscriber_remove
-func (s *ChannelService) RemoveSubscribers(ctx context.Context, req *RemoveSubscribersRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) RemoveSubscribers(ctx context.Context, req *RemoveSubscribersRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/subscriber_remove", req, &respBody)
+	err := s.client.do(ctx, "channel.RemoveSubscribers", http.MethodPost, "/channel/subscriber_remove", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.RemoveSubscribers", err)
 	}
@@ -118,13 +117,13 @@ type DeleteChannelRequest struct {
 
 // Delete 删除频道
 // POST /channel/delete
-func (s *ChannelService) Delete(ctx context.Context, req *DeleteChannelRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) Delete(ctx context.Context, req *DeleteChannelRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/delete", req, &respBody)
+	err := s.client.do(ctx, "channel.Delete", http.MethodPost, "/channel/delete", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.Delete", err)
 	}
@@ -140,13 +139,13 @@ type ChannelUIDsRequest struct {
 
 // AddBlacklist 添加频道黑名单
 // POST /channel/blacklist_add
-func (s *ChannelService) AddBlacklist(ctx context.Context, req *ChannelUIDsRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) AddBlacklist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/blacklist_add", req, &respBody)
+	err := s.client.do(ctx, "channel.AddBlacklist", http.MethodPost, "/channel/blacklist_add", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.AddBlacklist", err)
 	}
@@ -155,13 +154,13 @@ func (s *ChannelService) AddBlacklist(ctx context.Context, req *ChannelUIDsReque
 
 // SetBlacklist 设置频道黑名单（替换）
 // POST /channel/blacklist_set
-func (s *ChannelService) SetBlacklist(ctx context.Context, req *ChannelUIDsRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) SetBlacklist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/blacklist_set", req, &respBody)
+	err := s.client.do(ctx, "channel.SetBlacklist", http.MethodPost, "/channel/blacklist_set", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.SetBlacklist", err)
 	}
@@ -177,13 +176,13 @@ type RemoveBlacklistRequest struct {
 
 // RemoveBlacklist 移除频道黑名单
 // POST /channel/blacklist_remove
-func (s *ChannelService) RemoveBlacklist(ctx context.Context, req *RemoveBlacklistRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) RemoveBlacklist(ctx context.Context, req *RemoveBlacklistRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/blacklist_remove", req, &respBody)
+	err := s.client.do(ctx, "channel.RemoveBlacklist", http.MethodPost, "/channel/blacklist_remove", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.RemoveBlacklist", err)
 	}
@@ -192,13 +191,13 @@ func (s *ChannelService) RemoveBlacklist(ctx context.Context, req *RemoveBlackli
 
 // AddWhitelist 添加频道白名单
 // POST /channel/whitelist_add
-func (s *ChannelService) AddWhitelist(ctx context.Context, req *ChannelUIDsRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) AddWhitelist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/whitelist_add", req, &respBody)
+	err := s.client.do(ctx, "channel.AddWhitelist", http.MethodPost, "/channel/whitelist_add", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.AddWhitelist", err)
 	}
@@ -207,13 +206,13 @@ func (s *ChannelService) AddWhitelist(ctx context.Context, req *ChannelUIDsReque
 
 // SetWhitelist 设置频道白名单（替换）
 // POST /channel/whitelist_set
-func (s *ChannelService) SetWhitelist(ctx context.Context, req *ChannelUIDsRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) SetWhitelist(ctx context.Context, req *ChannelUIDsRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/whitelist_set", req, &respBody)
+	err := s.client.do(ctx, "channel.SetWhitelist", http.MethodPost, "/channel/whitelist_set", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.SetWhitelist", err)
 	}
@@ -229,13 +228,13 @@ type RemoveWhitelistRequest struct {
 
 // RemoveWhitelist 移除频道白名单
 // POST /channel/whitelist_remove
-func (s *ChannelService) RemoveWhitelist(ctx context.Context, req *RemoveWhitelistRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) RemoveWhitelist(ctx context.Context, req *RemoveWhitelistRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/whitelist_remove", req, &respBody)
+	err := s.client.do(ctx, "channel.RemoveWhitelist", http.MethodPost, "/channel/whitelist_remove", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.RemoveWhitelist", err)
 	}
@@ -249,18 +248,15 @@ type GetWhitelistRequest struct {
 }
 
 // GetWhitelist 获取频道白名单
-// GET /channel/whitelist
-func (s *ChannelService) GetWhitelist(ctx context.Context, req *GetWhitelistRequest) ([]string, error) {
+// GET /channel/whitelist?channel_id=xxx&channel_type=2
+func (s *ChannelService) GetWhitelist(ctx context.Context, req *GetWhitelistRequest, opts ...CallOption) ([]string, error) {
 	if req == nil {
 		return nil, nil
 	}
 
-	// 这里直接使用文档中的查询参数形式
-	// /channel/whitelist?channel_id=xxx&channel_type=2
-	path := "/channel/whitelist?channel_id=" + req.ChannelID + "&channel_type=" + fmt.Sprint(req.ChannelType)
-
 	var respBody []string
-	_, err := s.client.do(ctx, http.MethodGet, path, nil, &respBody)
+	query := channelQuery(req.ChannelID, req.ChannelType)
+	err := s.client.do(ctx, "channel.GetWhitelist", http.MethodGet, "/channel/whitelist", query, nil, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.GetWhitelist", err)
 	}
@@ -276,13 +272,13 @@ type SetTmpSubscriberRequest struct {
 
 // SetTmpSubscriber 设置临时频道订阅者
 // POST /channel/tmp_subscriber_set
-func (s *ChannelService) SetTmpSubscriber(ctx context.Context, req *SetTmpSubscriberRequest) (*CreateChannelResponse, error) {
+func (s *ChannelService) SetTmpSubscriber(ctx context.Context, req *SetTmpSubscriberRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/tmp_subscriber_set", req, &respBody)
+	err := s.client.do(ctx, "channel.SetTmpSubscriber", http.MethodPost, "/channel/tmp_subscriber_set", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("channel.SetTmpSubscriber", err)
 	}
@@ -19,13 +19,13 @@ type ConnectionRequest struct {
 
 // Remove 移除连接
 // POST /conn/remove
-func (s *ConnectionService) Remove(ctx context.Context, req *ConnectionRequest) (*CreateChannelResponse, error) {
+func (s *ConnectionService) Remove(ctx context.Context, req *ConnectionRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/conn/remove", req, &respBody)
+	err := s.client.do(ctx, "connection.Remove", http.MethodPost, "/conn/remove", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("connection.Remove", err)
 	}
@@ -34,13 +34,13 @@ func (s *ConnectionService) Remove(ctx context.Context, req *ConnectionRequest)
 
 // Kick 踢出连接
 // POST /conn/kick
-func (s *ConnectionService) Kick(ctx context.Context, req *ConnectionRequest) (*CreateChannelResponse, error) {
+func (s *ConnectionService) Kick(ctx context.Context, req *ConnectionRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/conn/kick", req, &respBody)
+	err := s.client.do(ctx, "connection.Kick", http.MethodPost, "/conn/kick", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("connection.Kick", err)
 	}
@@ -43,13 +43,13 @@ type Conversation struct {
 
 // Sync 同步用户会话
 // POST /conversation/sync
-func (s *ConversationService) Sync(ctx context.Context, req *ConversationSyncRequest) ([]Conversation, error) {
+func (s *ConversationService) Sync(ctx context.Context, req *ConversationSyncRequest, opts ...CallOption) ([]Conversation, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody []Conversation
-	_, err := s.client.do(ctx, http.MethodPost, "/conversation/sync", req, &respBody)
+	err := s.client.do(ctx, "conversation.Sync", http.MethodPost, "/conversation/sync", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("conversation.Sync", err)
 	}
@@ -66,13 +66,13 @@ type ConversationClearUnreadRequest struct {
 
 // ClearUnread 清除未读消息
 // POST /conversations/clearUnread
-func (s *ConversationService) ClearUnread(ctx context.Context, req *ConversationClearUnreadRequest) (*CreateChannelResponse, error) {
+func (s *ConversationService) ClearUnread(ctx context.Context, req *ConversationClearUnreadRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/conversations/clearUnread", req, &respBody)
+	err := s.client.do(ctx, "conversation.ClearUnread", http.MethodPost, "/conversations/clearUnread", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("conversation.ClearUnread", err)
 	}
@@ -89,13 +89,13 @@ type ConversationSetUnreadRequest struct {
 
 // SetUnread 设置会话未读数
 // POST /conversations/setUnread
-func (s *ConversationService) SetUnread(ctx context.Context, req *ConversationSetUnreadRequest) (*CreateChannelResponse, error) {
+func (s *ConversationService) SetUnread(ctx context.Context, req *ConversationSetUnreadRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/conversations/setUnread", req, &respBody)
+	err := s.client.do(ctx, "conversation.SetUnread", http.MethodPost, "/conversations/setUnread", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("conversation.SetUnread", err)
 	}
@@ -111,13 +111,13 @@ type ConversationDeleteRequest struct {
 
 // Delete 删除会话
 // POST /conversations/delete
-func (s *ConversationService) Delete(ctx context.Context, req *ConversationDeleteRequest) (*CreateChannelResponse, error) {
+func (s *ConversationService) Delete(ctx context.Context, req *ConversationDeleteRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/conversations/delete", req, &respBody)
+	err := s.client.do(ctx, "conversation.Delete", http.MethodPost, "/conversations/delete", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("conversation.Delete", err)
 	}
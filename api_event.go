@@ -2,8 +2,9 @@ package wukong_go_sdk
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 // EventService 事件相关接口
@@ -31,18 +32,19 @@ type EventSendRequest struct {
 
 // Send 发送事件
 // POST /event
-func (s *EventService) Send(ctx context.Context, req *EventSendRequest) (*CreateChannelResponse, error) {
+func (s *EventService) Send(ctx context.Context, req *EventSendRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
-	path := "/event"
+	var query url.Values
 	if req.ForceEnd != nil {
-		path = fmt.Sprintf("/event?force_end=%d", *req.ForceEnd)
+		query = url.Values{}
+		query.Set("force_end", strconv.Itoa(*req.ForceEnd))
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, path, req, &respBody)
+	err := s.client.do(ctx, "event.Send", http.MethodPost, "/event", query, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("event.Send", err)
 	}
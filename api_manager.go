@@ -32,13 +32,13 @@ type ManagerLoginResponse struct {
 
 // Login 管理员登录
 // POST /manager/login
-func (s *ManagerService) Login(ctx context.Context, req *ManagerLoginRequest) (*ManagerLoginResponse, error) {
+func (s *ManagerService) Login(ctx context.Context, req *ManagerLoginRequest, opts ...CallOption) (*ManagerLoginResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody ManagerLoginResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/manager/login", req, &respBody)
+	err := s.client.do(ctx, "manager.Login", http.MethodPost, "/manager/login", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("manager.Login", err)
 	}
@@ -2,7 +2,10 @@ package wukong_go_sdk
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 )
 
 // MessageService 消息相关接口
@@ -38,13 +41,13 @@ type SendMessageResponse struct {
 
 // SendMessage 发送单条消息
 // POST /message/send
-func (s *MessageService) SendMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageResponse, error) {
+func (s *MessageService) SendMessage(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendMessageResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody SendMessageResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/message/send", req, &respBody)
+	err := s.client.do(ctx, "message.SendMessage", http.MethodPost, "/message/send", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.SendMessage", err)
 	}
@@ -65,13 +68,13 @@ type BatchSendMessageResponseItem struct {
 
 // BatchSendMessage 批量发送消息
 // POST /message/sendbatch
-func (s *MessageService) BatchSendMessage(ctx context.Context, req *BatchSendMessageRequest) ([]BatchSendMessageResponseItem, error) {
+func (s *MessageService) BatchSendMessage(ctx context.Context, req *BatchSendMessageRequest, opts ...CallOption) ([]BatchSendMessageResponseItem, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody []BatchSendMessageResponseItem
-	_, err := s.client.do(ctx, http.MethodPost, "/message/sendbatch", req.Messages, &respBody)
+	err := s.client.do(ctx, "message.BatchSendMessage", http.MethodPost, "/message/sendbatch", nil, req.Messages, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.BatchSendMessage", err)
 	}
@@ -103,13 +106,13 @@ type MessageSyncRequest struct {
 
 // MessageSync 同步频道历史消息
 // POST /channel/messagesync
-func (s *MessageService) MessageSync(ctx context.Context, req *MessageSyncRequest) ([]Message, error) {
+func (s *MessageService) MessageSync(ctx context.Context, req *MessageSyncRequest, opts ...CallOption) ([]Message, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody []Message
-	_, err := s.client.do(ctx, http.MethodPost, "/channel/messagesync", req, &respBody)
+	err := s.client.do(ctx, "message.MessageSync", http.MethodPost, "/channel/messagesync", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.MessageSync", err)
 	}
@@ -118,6 +121,8 @@ func (s *MessageService) MessageSync(ctx context.Context, req *MessageSyncReques
 
 // MaxMessageSeqRequest 获取频道最大消息序号请求
 type MaxMessageSeqRequest struct {
+	// LoginUID 个人频道必填，ChannelID 为对方 UID
+	LoginUID    string
 	ChannelID   string
 	ChannelType ChannelType
 }
@@ -128,20 +133,96 @@ type MaxMessageSeqResponse struct {
 }
 
 // GetMaxMessageSeq 获取频道最大消息序号
-// GET /channel/max_message_seq
-func (s *MessageService) GetMaxMessageSeq(ctx context.Context, req *MaxMessageSeqRequest) (*MaxMessageSeqResponse, error) {
+// GET /channel/max_message_seq?channel_id=xxx&channel_type=2&login_uid=xxx
+func (s *MessageService) GetMaxMessageSeq(ctx context.Context, req *MaxMessageSeqRequest, opts ...CallOption) (*MaxMessageSeqResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody MaxMessageSeqResponse
-	_, err := s.client.do(ctx, http.MethodGet, "/channel/max_message_seq", nil, &respBody)
+	query := channelQuery(req.ChannelID, req.ChannelType)
+	if req.LoginUID != "" {
+		query.Set("login_uid", req.LoginUID)
+	}
+	err := s.client.do(ctx, "message.GetMaxMessageSeq", http.MethodGet, "/channel/max_message_seq", query, nil, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.GetMaxMessageSeq", err)
 	}
 	return &respBody, nil
 }
 
+// BatchMaxMessageSeqRequest 批量获取频道最大消息序号请求
+type BatchMaxMessageSeqRequest struct {
+	// LoginUID 查询个人频道时使用的登录用户，对所有频道生效
+	LoginUID string
+	Channels []ChannelKey
+	// Concurrency 最大并发请求数，默认 8
+	Concurrency int
+}
+
+// BatchGetMaxMessageSeq 并发获取多个频道的最大消息序号，并发数受 Concurrency 限制
+// 返回成功频道的结果；部分频道失败时同时返回由 errors.Join 合并的错误，每个错误都带有频道信息
+func (s *MessageService) BatchGetMaxMessageSeq(ctx context.Context, req *BatchMaxMessageSeqRequest, opts ...CallOption) (map[ChannelKey]int64, error) {
+	return BatchGetMaxMessageSeq(ctx, s, req, opts...)
+}
+
+// BatchGetMaxMessageSeq 通过 api.GetMaxMessageSeq 并发获取多个频道的最大消息序号，行为与 MessageService.BatchGetMaxMessageSeq 相同
+func BatchGetMaxMessageSeq(ctx context.Context, api MessageAPI, req *BatchMaxMessageSeqRequest, opts ...CallOption) (map[ChannelKey]int64, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		out  = make(map[ChannelKey]int64, len(req.Channels))
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+		seen = make(map[ChannelKey]bool, len(req.Channels))
+	)
+	for _, key := range req.Channels {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("channel %s: %w", key, ctx.Err()))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(key ChannelKey) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			resp, err := api.GetMaxMessageSeq(ctx, &MaxMessageSeqRequest{LoginUID: req.LoginUID, ChannelID: key.ChannelID, ChannelType: key.ChannelType}, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("channel %s: %w", key, err))
+				return
+			}
+			out[key] = resp.MaxMessageSeq
+		}(key)
+	}
+	wg.Wait()
+
+	return out, errors.Join(errs...)
+}
+
 // UserSearchRequest 用户消息搜索请求
 type UserSearchRequest struct {
 	UID          string         `json:"uid"`
@@ -177,13 +258,13 @@ type UserSearchResult struct {
 
 // UserSearch 用户消息搜索
 // POST /plugins/wk.plugin.search/usersearch
-func (s *MessageService) UserSearch(ctx context.Context, req *UserSearchRequest) (*UserSearchResponse, error) {
+func (s *MessageService) UserSearch(ctx context.Context, req *UserSearchRequest, opts ...CallOption) (*UserSearchResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody UserSearchResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/plugins/wk.plugin.search/usersearch", req, &respBody)
+	err := s.client.do(ctx, "message.UserSearch", http.MethodPost, "/plugins/wk.plugin.search/usersearch", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.UserSearch", err)
 	}
@@ -197,13 +278,13 @@ type BatchSearchRequest struct {
 
 // BatchSearch 批量消息搜索
 // POST /messages
-func (s *MessageService) BatchSearch(ctx context.Context, req *BatchSearchRequest) ([]Message, error) {
+func (s *MessageService) BatchSearch(ctx context.Context, req *BatchSearchRequest, opts ...CallOption) ([]Message, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody []Message
-	_, err := s.client.do(ctx, http.MethodPost, "/messages", req, &respBody)
+	err := s.client.do(ctx, "message.BatchSearch", http.MethodPost, "/messages", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.BatchSearch", err)
 	}
@@ -217,13 +298,13 @@ type SingleSearchRequest struct {
 
 // SingleSearch 单条消息搜索
 // POST /message
-func (s *MessageService) SingleSearch(ctx context.Context, req *SingleSearchRequest) (*Message, error) {
+func (s *MessageService) SingleSearch(ctx context.Context, req *SingleSearchRequest, opts ...CallOption) (*Message, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody Message
-	_, err := s.client.do(ctx, http.MethodPost, "/message", req, &respBody)
+	err := s.client.do(ctx, "message.SingleSearch", http.MethodPost, "/message", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("message.SingleSearch", err)
 	}
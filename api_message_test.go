@@ -0,0 +1,58 @@
+package wukong_go_sdk_test
+
+import (
+	"context"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+)
+
+// sendPerson 从 from 向 to 发送 n 条单聊消息
+func sendPerson(t *testing.T, cli *wukong.Client, from, to string, n int) {
+	t.Helper()
+	for range n {
+		_, err := cli.Message.SendMessage(context.Background(), &wukong.SendMessageRequest{
+			FromUID:     from,
+			ChannelID:   to,
+			ChannelType: wukong.ChannelTypePerson,
+			Payload:     "aGk=",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetMaxMessageSeqPersonChannel(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	sendPerson(t, cli, "u1", "u2", 3)
+
+	resp, err := cli.Message.GetMaxMessageSeq(ctx, &wukong.MaxMessageSeqRequest{LoginUID: "u2", ChannelID: "u1", ChannelType: wukong.ChannelTypePerson})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.MaxMessageSeq != 3 {
+		t.Errorf("MaxMessageSeq = %d, want 3", resp.MaxMessageSeq)
+	}
+	req, _ := srv.LastRequest("/channel/max_message_seq")
+	if got := req.Query.Get("login_uid"); got != "u2" {
+		t.Errorf("login_uid = %q, want u2", got)
+	}
+
+	peer := wukong.ChannelKey{ChannelID: "u2", ChannelType: wukong.ChannelTypePerson}
+	batch, err := cli.Message.BatchGetMaxMessageSeq(ctx, &wukong.BatchMaxMessageSeqRequest{
+		LoginUID: "u1",
+		Channels: []wukong.ChannelKey{peer},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch[peer] != 3 {
+		t.Errorf("batch max seq = %d, want 3", batch[peer])
+	}
+}
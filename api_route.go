@@ -2,7 +2,6 @@ package wukong_go_sdk
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
@@ -26,18 +25,13 @@ type RouteAddress struct {
 
 // GetIMAddress 获取用户 IM 地址
 // GET /route?intranet=0
-func (s *RouteService) GetIMAddress(ctx context.Context, req *RouteAddressRequest) (*RouteAddress, error) {
+func (s *RouteService) GetIMAddress(ctx context.Context, req *RouteAddressRequest, opts ...CallOption) (*RouteAddress, error) {
 	if req == nil {
 		req = &RouteAddressRequest{}
 	}
 
-	path := "/route"
-	if req.Intranet == IntranetTypeExternal || req.Intranet == IntranetTypeInternal {
-		path = fmt.Sprintf("/route?intranet=%d", req.Intranet)
-	}
-
 	var respBody RouteAddress
-	_, err := s.client.do(ctx, http.MethodGet, path, nil, &respBody)
+	err := s.client.do(ctx, "route.GetIMAddress", http.MethodGet, "/route", intranetQuery(req.Intranet), nil, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("route.GetIMAddress", err)
 	}
@@ -62,18 +56,13 @@ type BatchRouteAddress struct {
 
 // BatchGetIMAddress 批量获取用户 IM 地址
 // POST /route/batch?intranet=0
-func (s *RouteService) BatchGetIMAddress(ctx context.Context, req *BatchRouteAddressRequest) ([]BatchRouteAddress, error) {
+func (s *RouteService) BatchGetIMAddress(ctx context.Context, req *BatchRouteAddressRequest, opts ...CallOption) ([]BatchRouteAddress, error) {
 	if req == nil {
 		return nil, nil
 	}
 
-	path := "/route/batch"
-	if req.Intranet == IntranetTypeExternal || req.Intranet == IntranetTypeInternal {
-		path = fmt.Sprintf("/route/batch?intranet=%d", req.Intranet)
-	}
-
 	var respBody []BatchRouteAddress
-	_, err := s.client.do(ctx, http.MethodPost, path, req.UIDs, &respBody)
+	err := s.client.do(ctx, "route.BatchGetIMAddress", http.MethodPost, "/route/batch", intranetQuery(req.Intranet), req.UIDs, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("route.BatchGetIMAddress", err)
 	}
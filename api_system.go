@@ -18,9 +18,9 @@ type HealthStatus struct {
 
 // Health 健康检查
 // GET /health
-func (s *SystemService) Health(ctx context.Context) (*HealthStatus, error) {
+func (s *SystemService) Health(ctx context.Context, opts ...CallOption) (*HealthStatus, error) {
 	var respBody HealthStatus
-	_, err := s.client.do(ctx, http.MethodGet, "/health", nil, &respBody)
+	err := s.client.do(ctx, "system.Health", http.MethodGet, "/health", nil, nil, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("system.Health", err)
 	}
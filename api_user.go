@@ -20,13 +20,13 @@ type UpdateUserTokenRequest struct {
 
 // UpdateToken 更新用户 Token
 // POST /user/token
-func (s *UserService) UpdateToken(ctx context.Context, req *UpdateUserTokenRequest) (*CreateChannelResponse, error) {
+func (s *UserService) UpdateToken(ctx context.Context, req *UpdateUserTokenRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/user/token", req, &respBody)
+	err := s.client.do(ctx, "user.UpdateToken", http.MethodPost, "/user/token", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.UpdateToken", err)
 	}
@@ -41,13 +41,13 @@ type DeviceQuitRequest struct {
 
 // DeviceQuit 强制设备退出
 // POST /user/device_quit
-func (s *UserService) DeviceQuit(ctx context.Context, req *DeviceQuitRequest) (*CreateChannelResponse, error) {
+func (s *UserService) DeviceQuit(ctx context.Context, req *DeviceQuitRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/user/device_quit", req, &respBody)
+	err := s.client.do(ctx, "user.DeviceQuit", http.MethodPost, "/user/device_quit", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.DeviceQuit", err)
 	}
@@ -68,13 +68,13 @@ type UserOnlineStatus struct {
 
 // OnlineStatus 获取用户在线状态
 // POST /user/onlinestatus
-func (s *UserService) OnlineStatus(ctx context.Context, req *OnlineStatusRequest) ([]UserOnlineStatus, error) {
+func (s *UserService) OnlineStatus(ctx context.Context, req *OnlineStatusRequest, opts ...CallOption) ([]UserOnlineStatus, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody []UserOnlineStatus
-	_, err := s.client.do(ctx, http.MethodPost, "/user/onlinestatus", req.UIDs, &respBody)
+	err := s.client.do(ctx, "user.OnlineStatus", http.MethodPost, "/user/onlinestatus", nil, req.UIDs, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.OnlineStatus", err)
 	}
@@ -83,9 +83,9 @@ func (s *UserService) OnlineStatus(ctx context.Context, req *OnlineStatusRequest
 
 // SystemUIDs 获取系统用户 ID 列表
 // GET /user/systemuids
-func (s *UserService) SystemUIDs(ctx context.Context) ([]string, error) {
+func (s *UserService) SystemUIDs(ctx context.Context, opts ...CallOption) ([]string, error) {
 	var respBody []string
-	_, err := s.client.do(ctx, http.MethodGet, "/user/systemuids", nil, &respBody)
+	err := s.client.do(ctx, "user.SystemUIDs", http.MethodGet, "/user/systemuids", nil, nil, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.SystemUIDs", err)
 	}
@@ -99,13 +99,13 @@ type SystemUIDsChangeRequest struct {
 
 // AddSystemUIDs 添加系统用户 ID
 // POST /user/systemuids_add
-func (s *UserService) AddSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest) (*CreateChannelResponse, error) {
+func (s *UserService) AddSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/user/systemuids_add", req, &respBody)
+	err := s.client.do(ctx, "user.AddSystemUIDs", http.MethodPost, "/user/systemuids_add", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.AddSystemUIDs", err)
 	}
@@ -114,13 +114,13 @@ func (s *UserService) AddSystemUIDs(ctx context.Context, req *SystemUIDsChangeRe
 
 // RemoveSystemUIDs 移除系统用户 ID
 // POST /user/systemuids_remove
-func (s *UserService) RemoveSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest) (*CreateChannelResponse, error) {
+func (s *UserService) RemoveSystemUIDs(ctx context.Context, req *SystemUIDsChangeRequest, opts ...CallOption) (*CreateChannelResponse, error) {
 	if req == nil {
 		return nil, nil
 	}
 
 	var respBody CreateChannelResponse
-	_, err := s.client.do(ctx, http.MethodPost, "/user/systemuids_remove", req, &respBody)
+	err := s.client.do(ctx, "user.RemoveSystemUIDs", http.MethodPost, "/user/systemuids_remove", nil, req, &respBody, opts...)
 	if err != nil {
 		return nil, wrapError("user.RemoveSystemUIDs", err)
 	}
@@ -0,0 +1,321 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器处于打开状态，请求未发出即被拒绝
+var ErrCircuitOpen = errors.New("wukongim: circuit breaker is open")
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	// CircuitClosed 正常放行请求
+	CircuitClosed CircuitState = 0
+	// CircuitOpen 拒绝所有请求，冷却时间结束后进入半开状态
+	CircuitOpen CircuitState = 1
+	// CircuitHalfOpen 放行少量试探请求，成功则关闭，失败则重新打开
+	CircuitHalfOpen CircuitState = 2
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerConfig 熔断器配置，通过 Config.CircuitBreaker 开启
+// 熔断器按节点划分，开启 PerOperation 后进一步按操作名划分
+type CircuitBreakerConfig struct {
+	// FailureRatio 统计窗口内失败比例达到该值时打开熔断器，默认 0.5
+	FailureRatio float64
+	// MinRequests 统计窗口内至少有这么多请求才会判断失败比例，默认 10
+	MinRequests int
+	// Window 关闭状态下的统计窗口，默认 10s
+	Window time.Duration
+	// CoolDown 打开后经过多久进入半开状态，默认 5s
+	CoolDown time.Duration
+	// HalfOpenRequests 半开状态下允许的试探请求数，全部成功后关闭熔断器，默认 1
+	HalfOpenRequests int
+
+	// PerOperation 是否按操作名（例如 message.SendMessage）分别熔断
+	PerOperation bool
+
+	// OnStateChange 状态变化时回调，可用于告警
+	OnStateChange func(change CircuitStateChange)
+}
+
+// CircuitStateChange 描述一次熔断器状态变化
+type CircuitStateChange struct {
+	Endpoint string
+	// Operation 仅在 PerOperation 开启时有值
+	Operation string
+	From      CircuitState
+	To        CircuitState
+}
+
+// CircuitMetricsRecorder 是 MetricsRecorder 的可选扩展
+// Config.Metrics 实现了该接口时，熔断器的状态变化与拒绝次数也会被上报
+type CircuitMetricsRecorder interface {
+	CircuitStateChanged(change CircuitStateChange)
+	CircuitRejected(endpoint, operation string)
+}
+
+func (cfg *CircuitBreakerConfig) failureRatio() float64 {
+	if cfg.FailureRatio <= 0 {
+		return 0.5
+	}
+	return cfg.FailureRatio
+}
+
+func (cfg *CircuitBreakerConfig) minRequests() int {
+	if cfg.MinRequests <= 0 {
+		return 10
+	}
+	return cfg.MinRequests
+}
+
+func (cfg *CircuitBreakerConfig) window() time.Duration {
+	if cfg.Window <= 0 {
+		return 10 * time.Second
+	}
+	return cfg.Window
+}
+
+func (cfg *CircuitBreakerConfig) coolDown() time.Duration {
+	if cfg.CoolDown <= 0 {
+		return 5 * time.Second
+	}
+	return cfg.CoolDown
+}
+
+func (cfg *CircuitBreakerConfig) halfOpenRequests() int {
+	if cfg.HalfOpenRequests <= 0 {
+		return 1
+	}
+	return cfg.HalfOpenRequests
+}
+
+// outcome 一次请求对熔断器和节点健康的影响
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored 调用方取消、调用超时等与节点无关的结果
+	outcomeIgnored
+)
+
+// classifyOutcome 传输层错误、超时与 5xx 视为节点故障，4xx 属于业务错误，视为成功
+// 调用方的 ctx 已经结束（取消或超过截止时间）时，失败与节点无关，不计入统计
+func classifyOutcome(ctx context.Context, statusCode int, err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil {
+		return outcomeIgnored
+	}
+	if statusCode == 0 || statusCode >= 500 {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// breaker 单个作用域（节点或节点+操作）的熔断器
+type breaker struct {
+	cfg       *CircuitBreakerConfig
+	endpoint  string
+	operation string
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	halfOpen    int // 半开状态下已放行的试探请求数
+	halfOpenOK  int // 半开状态下成功的试探请求数
+}
+
+// allow 判断是否放行请求，返回的 change 非 nil 表示状态发生了变化
+func (b *breaker) allow(now time.Time) (bool, *CircuitStateChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var change *CircuitStateChange
+	if b.state == CircuitOpen {
+		if now.Sub(b.openedAt) < b.cfg.coolDown() {
+			return false, nil
+		}
+		change = b.transition(CircuitHalfOpen, now)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.halfOpen >= b.cfg.halfOpenRequests() {
+			return false, change
+		}
+		b.halfOpen++
+	}
+	return true, change
+}
+
+// record 记录一次请求结果，返回的 change 非 nil 表示状态发生了变化
+func (b *breaker) record(result outcome, now time.Time) *CircuitStateChange {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		switch result {
+		case outcomeFailure:
+			return b.transition(CircuitOpen, now)
+		case outcomeSuccess:
+			b.halfOpenOK++
+			if b.halfOpenOK >= b.cfg.halfOpenRequests() {
+				return b.transition(CircuitClosed, now)
+			}
+		case outcomeIgnored:
+			// 释放试探名额
+			b.halfOpen--
+		}
+	case CircuitClosed:
+		if result == outcomeIgnored {
+			return nil
+		}
+		if now.Sub(b.windowStart) >= b.cfg.window() {
+			b.windowStart = now
+			b.requests, b.failures = 0, 0
+		}
+		b.requests++
+		if result == outcomeFailure {
+			b.failures++
+		}
+		if b.requests >= b.cfg.minRequests() &&
+			float64(b.failures)/float64(b.requests) >= b.cfg.failureRatio() {
+			return b.transition(CircuitOpen, now)
+		}
+	}
+	return nil
+}
+
+// transition 切换状态并重置计数，调用方需持有锁
+func (b *breaker) transition(to CircuitState, now time.Time) *CircuitStateChange {
+	change := &CircuitStateChange{
+		Endpoint:  b.endpoint,
+		Operation: b.operation,
+		From:      b.state,
+		To:        to,
+	}
+
+	b.state = to
+	b.windowStart = now
+	b.requests, b.failures = 0, 0
+	b.halfOpen, b.halfOpenOK = 0, 0
+	if to == CircuitOpen {
+		b.openedAt = now
+	}
+	return change
+}
+
+// breakerSet 按作用域懒创建熔断器
+type breakerSet struct {
+	cfg *CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func newBreakerSet(cfg *CircuitBreakerConfig) *breakerSet {
+	return &breakerSet{cfg: cfg, breakers: make(map[string]*breaker)}
+}
+
+func (s *breakerSet) get(endpoint, operation string) *breaker {
+	if !s.cfg.PerOperation {
+		operation = ""
+	}
+	key := endpoint + "|" + operation
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.breakers[key]
+	if !ok {
+		b = &breaker{
+			cfg:         s.cfg,
+			endpoint:    endpoint,
+			operation:   operation,
+			windowStart: time.Now(),
+		}
+		s.breakers[key] = b
+	}
+	return b
+}
+
+// notifyCircuit 把状态变化通知给回调与指标
+func (c *Client) notifyCircuit(change *CircuitStateChange) {
+	if change == nil {
+		return
+	}
+	if c.breakers.cfg.OnStateChange != nil {
+		c.breakers.cfg.OnStateChange(*change)
+	}
+	if rec, ok := c.cfg.Metrics.(CircuitMetricsRecorder); ok {
+		rec.CircuitStateChanged(*change)
+	}
+}
+
+// selectEndpoint 为本次尝试选择节点，并检查对应的熔断器
+// 多节点时会跳过熔断器打开的节点；所有可选节点都被熔断时返回 ErrCircuitOpen
+func (c *Client) selectEndpoint(op *Operation, tried []*endpoint) (*endpoint, *breaker, error) {
+	var ep *endpoint
+	if c.pool != nil {
+		ep = c.pool.pick(tried)
+	}
+	if c.breakers == nil {
+		return ep, nil, nil
+	}
+
+	var rejected []*endpoint
+	skip := tried
+	for {
+		url := ""
+		if ep != nil {
+			url = ep.url
+		}
+
+		br := c.breakers.get(url, op.Name)
+		ok, change := br.allow(time.Now())
+		c.notifyCircuit(change)
+		if ok {
+			return ep, br, nil
+		}
+
+		if rec, isRec := c.cfg.Metrics.(CircuitMetricsRecorder); isRec {
+			rec.CircuitRejected(br.endpoint, br.operation)
+		}
+		if ep == nil || len(rejected) >= len(c.pool.endpoints)-1 {
+			return nil, nil, fmt.Errorf("%w: endpoint %q", ErrCircuitOpen, url)
+		}
+
+		rejected = append(rejected, ep)
+		skip = append(skip[:len(skip):len(skip)], ep)
+		next := c.pool.pick(skip)
+		for _, r := range rejected {
+			if r == next {
+				return nil, nil, fmt.Errorf("%w: endpoint %q", ErrCircuitOpen, url)
+			}
+		}
+		ep = next
+	}
+}
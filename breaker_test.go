@@ -0,0 +1,163 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newSlowServer(t *testing.T, latency time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/user/onlinestatus" {
+			_, _ = io.WriteString(w, `[]`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCallerDeadlineDoesNotTripBreaker(t *testing.T) {
+	a, b := newSlowServer(t, 50*time.Millisecond), newSlowServer(t, 50*time.Millisecond)
+
+	var (
+		mu      sync.Mutex
+		changes []CircuitStateChange
+	)
+	cli := NewClient(Config{
+		Endpoints:           []string{a.URL, b.URL},
+		HealthCheckInterval: time.Hour,
+		CircuitBreaker: &CircuitBreakerConfig{
+			MinRequests: 2,
+			OnStateChange: func(c CircuitStateChange) {
+				mu.Lock()
+				changes = append(changes, c)
+				mu.Unlock()
+			},
+		},
+	})
+	defer cli.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		_, err := cli.User.OnlineStatus(ctx, &OnlineStatusRequest{UIDs: []string{"u1"}}, WithCallTimeout(10*time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("call %d: err = %v, want deadline exceeded", i, err)
+		}
+	}
+	// 调用方取消同样不计入
+	canceled, cancel := context.WithCancel(ctx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := cli.System.Health(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want canceled", err)
+	}
+
+	for _, s := range cli.Endpoints() {
+		if !s.Healthy || s.Failures != 0 {
+			t.Errorf("endpoint %+v was penalized for the caller's deadline", s)
+		}
+	}
+	mu.Lock()
+	if len(changes) != 0 {
+		t.Errorf("breaker changed state: %+v", changes)
+	}
+	mu.Unlock()
+
+	if _, err := cli.System.Health(ctx); err != nil {
+		t.Fatalf("Health after caller timeouts: %v", err)
+	}
+}
+
+func TestTransportTimeoutTripsBreaker(t *testing.T) {
+	srv := newSlowServer(t, 50*time.Millisecond)
+
+	cli := NewClient(Config{
+		BaseURL:        srv.URL,
+		Timeout:        10 * time.Millisecond,
+		CircuitBreaker: &CircuitBreakerConfig{MinRequests: 2, CoolDown: time.Hour},
+	})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := cli.System.Health(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: err = %v, want a transport timeout", i, err)
+		}
+	}
+	if _, err := cli.System.Health(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestDecodeErrorsDoNotTripBreaker(t *testing.T) {
+	srv, _ := newGarbledNode(t)
+
+	var changes atomic.Int32
+	cli := NewClient(Config{
+		BaseURL: srv.URL,
+		CircuitBreaker: &CircuitBreakerConfig{
+			MinRequests:   2,
+			CoolDown:      time.Hour,
+			OnStateChange: func(CircuitStateChange) { changes.Add(1) },
+		},
+	})
+	defer cli.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		_, err := cli.System.Health(ctx)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("call %d: err = %v, want *DecodeError", i, err)
+		}
+	}
+	if n := changes.Load(); n != 0 {
+		t.Errorf("breaker changed state %d times after 2xx decode errors", n)
+	}
+}
+
+func TestClassifyOutcome(t *testing.T) {
+	live := context.Background()
+	done, cancel := context.WithCancel(live)
+	cancel()
+	expired, cancel2 := context.WithDeadline(live, time.Now().Add(-time.Second))
+	defer cancel2()
+
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		status int
+		err    error
+		want   outcome
+	}{
+		{"success", live, 200, nil, outcomeSuccess},
+		{"client error", live, 400, errBoom, outcomeSuccess},
+		{"decode error", live, 200, &DecodeError{StatusCode: 200, Err: errBoom}, outcomeSuccess},
+		{"server error", live, 503, errBoom, outcomeFailure},
+		{"transport error", live, 0, errBoom, outcomeFailure},
+		{"transport timeout", live, 0, context.DeadlineExceeded, outcomeFailure},
+		{"caller canceled", done, 0, context.Canceled, outcomeIgnored},
+		{"caller deadline", expired, 0, context.DeadlineExceeded, outcomeIgnored},
+		{"server error after caller deadline", expired, 503, errBoom, outcomeIgnored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyOutcome(tt.ctx, tt.status, tt.err); got != tt.want {
+				t.Errorf("classifyOutcome = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package wukong_go_sdk
+
+import (
+	"net/http"
+	"time"
+)
+
+// CallOption 是单次调用的选项，所有服务方法都接受可变参数形式的 CallOption
+//
+//	var meta wukong.ResponseMeta
+//	_, err := cli.User.OnlineStatus(ctx, req,
+//		wukong.WithCallTimeout(500*time.Millisecond),
+//		wukong.WithRequestID(reqID),
+//		wukong.WithResponseCapture(&meta),
+//	)
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout time.Duration
+	header  http.Header
+	capture *ResponseMeta
+
+	retry    *RetryPolicy
+	retrySet bool
+}
+
+// ResponseMeta 记录一次调用的响应元数据，通过 WithResponseCapture 获取
+type ResponseMeta struct {
+	// StatusCode 最后一次尝试的 HTTP 状态码，未拿到响应时为 0
+	StatusCode int
+	// Header 最后一次尝试的响应头
+	Header http.Header
+	// Latency 整个调用的耗时，包括重试与等待
+	Latency time.Duration
+	// Endpoint 最后一次尝试使用的节点地址
+	Endpoint string
+	// Attempts 实际发出的请求次数
+	Attempts int
+	// Body 最后一次尝试的原始响应体
+	Body []byte
+}
+
+// WithCallTimeout 为本次调用设置超时，覆盖包括重试在内的整个调用过程
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHeader 为本次调用附加一个请求头
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithRequestID 为本次调用设置 X-Request-ID 请求头，便于与服务端日志关联
+func WithRequestID(id string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Set("X-Request-ID", id)
+	}
+}
+
+// WithRetryPolicy 为本次调用指定重试策略，覆盖 Config.Retry
+// p 为 nil 时本次调用不重试；非幂等请求（未设置 ClientMsgNo 的消息发送）仍然只会执行一次
+func WithRetryPolicy(p *RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = p
+		o.retrySet = true
+	}
+}
+
+// WithResponseCapture 在调用结束后把响应元数据写入 meta，调用失败时同样会填充
+func WithResponseCapture(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.capture = meta
+	}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
@@ -0,0 +1,166 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithCallTimeout(t *testing.T) {
+	srv := newSlowServer(t, 100*time.Millisecond)
+	cli := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second})
+	defer cli.Close()
+
+	ctx := context.Background()
+	if _, err := cli.System.Health(ctx); err != nil {
+		t.Fatalf("Health within Config.Timeout: %v", err)
+	}
+
+	start := time.Now()
+	_, err := cli.System.Health(ctx, WithCallTimeout(10*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrTimeout) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if d := time.Since(start); d >= 100*time.Millisecond {
+		t.Errorf("call returned after %v, want the call timeout to win", d)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		header http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		header = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+	}))
+	defer srv.Close()
+
+	cli := NewClient(Config{BaseURL: srv.URL}, WithDefaultHeaders(map[string]string{
+		"X-Tenant": "default",
+		"X-App":    "app1",
+	}))
+	defer cli.Close()
+
+	_, err := cli.System.Health(context.Background(),
+		WithHeader("X-Tenant", "t1"),
+		WithHeader("X-Trace", "a"),
+		WithHeader("X-Trace", "b"),
+		WithRequestID("req-1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同名的多个值由 resty 合并为一行
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"X-Tenant", "t1"},
+		{"X-App", "app1"},
+		{"X-Trace", "a, b"},
+		{"X-Request-Id", "req-1"},
+	}
+	mu.Lock()
+	for _, tt := range tests {
+		if got := strings.Join(header.Values(tt.key), ", "); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+	mu.Unlock()
+
+	// 调用选项只影响本次调用
+	if _, err := cli.System.Health(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := header.Get("X-Tenant"); got != "default" {
+		t.Errorf("next call X-Tenant = %q, want default", got)
+	}
+	if got := header.Get("X-Trace"); got != "" {
+		t.Errorf("next call X-Trace = %q, want none", got)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *RetryPolicy
+		call      func(*attemptRecorder) CallOption
+		wantHits  int32
+		wantRetry bool
+	}{
+		{
+			name:      "enable retries",
+			cfg:       nil,
+			call:      func(r *attemptRecorder) CallOption { return WithRetryPolicy(r.policy(3)) },
+			wantHits:  3,
+			wantRetry: true,
+		},
+		{
+			name:      "disable retries",
+			cfg:       &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			call:      func(*attemptRecorder) CallOption { return WithRetryPolicy(nil) },
+			wantHits:  1,
+			wantRetry: false,
+		},
+		{
+			name:      "replace config policy",
+			cfg:       &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond},
+			call:      func(r *attemptRecorder) CallOption { return WithRetryPolicy(r.policy(2)) },
+			wantHits:  2,
+			wantRetry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := newFlakyServer(t, 10)
+			cli := NewClient(Config{BaseURL: srv.URL, Retry: tt.cfg})
+			defer cli.Close()
+
+			var rec attemptRecorder
+			_, err := cli.System.Health(context.Background(), tt.call(&rec))
+			if !errors.Is(err, ErrServerUnavailable) {
+				t.Fatalf("err = %v, want 503", err)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+
+			var retryErr *RetryError
+			if errors.As(err, &retryErr) != tt.wantRetry {
+				t.Errorf("err = %v, RetryError present = %v, want %v", err, !tt.wantRetry, tt.wantRetry)
+			}
+			if tt.wantRetry && len(rec.attempts) != int(tt.wantHits) {
+				t.Errorf("call policy observed %d attempts, want %d", len(rec.attempts), tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicyKeepsIdempotencyGate(t *testing.T) {
+	srv, hits := newFlakyServer(t, 10)
+	cli := NewClient(Config{BaseURL: srv.URL})
+	defer cli.Close()
+
+	var rec attemptRecorder
+	_, err := cli.Message.SendMessage(context.Background(), &SendMessageRequest{}, WithRetryPolicy(rec.policy(3)))
+	if err == nil {
+		t.Fatal("SendMessage succeeded")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1 for a send without ClientMsgNo", got)
+	}
+}
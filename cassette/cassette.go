@@ -0,0 +1,411 @@
+// Package cassette 提供录制/回放 HTTP 交互的传输层，用于编写确定性的 SDK 测试
+//
+// 录制模式下请求会被转发到真实服务，请求/响应按操作名写入 dir 下的 JSON 文件，
+// 例如 message.SendMessage.json，Authorization 请求头与 token、password 字段会被替换为 [REDACTED]；
+// 回放模式下直接从文件返回响应，找不到匹配的录制时返回 ErrNoMatch
+//
+//	rec := cassette.New("testdata/cassettes", cassette.ModeFromEnv("WUKONG_RECORD"))
+//	cli := wukong.NewClient(wukong.Config{
+//		BaseURL:   "http://staging:5001",
+//		Token:     os.Getenv("WUKONG_TOKEN"),
+//		Transport: rec,
+//	})
+//
+// 匹配规则：请求方法、路径、查询参数以及规范化后的 JSON 请求体（字段顺序与空白无关）
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// ErrNoMatch 回放时没有找到与请求匹配的录制
+var ErrNoMatch = errors.New("cassette: no recorded interaction matches the request")
+
+// redacted 替换敏感信息使用的占位符
+const redacted = "[REDACTED]"
+
+// Mode 传输层的工作模式
+type Mode int
+
+const (
+	// ModeReplay 从文件回放，不访问网络
+	ModeReplay Mode = iota
+	// ModeRecord 转发到真实服务并录制，已有的录制文件会被覆盖
+	ModeRecord
+)
+
+// ModeFromEnv 环境变量 name 为 "1" 或 "true" 时返回 ModeRecord，否则返回 ModeReplay
+func ModeFromEnv(name string) Mode {
+	if v, _ := strconv.ParseBool(os.Getenv(name)); v {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Option 配置 Transport
+type Option func(*Transport)
+
+// WithRealTransport 设置录制模式下实际发出请求的传输层，默认 http.DefaultTransport
+func WithRealTransport(rt http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.real = rt
+	}
+}
+
+// WithScrubHeaders 追加需要隐藏的请求头/响应头，默认隐藏 Authorization 与 Token
+func WithScrubHeaders(names ...string) Option {
+	return func(t *Transport) {
+		for _, name := range names {
+			t.scrubHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithScrubFields 追加需要隐藏的 JSON 字段名（不区分大小写），默认隐藏 token 与 password
+func WithScrubFields(names ...string) Option {
+	return func(t *Transport) {
+		for _, name := range names {
+			t.scrubFields[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// Transport 实现 http.RoundTripper 的录制/回放传输层，可以赋值给 wukong.Config.Transport
+type Transport struct {
+	dir          string
+	mode         Mode
+	real         http.RoundTripper
+	scrubHeaders map[string]bool
+	scrubFields  map[string]bool
+
+	mu    sync.Mutex
+	files map[string]*file
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// New 创建录制/回放传输层，dir 为录制文件所在目录
+func New(dir string, mode Mode, opts ...Option) *Transport {
+	t := &Transport{
+		dir:          dir,
+		mode:         mode,
+		real:         http.DefaultTransport,
+		scrubHeaders: map[string]bool{"Authorization": true, "Token": true},
+		scrubFields:  map[string]bool{"token": true, "password": true},
+		files:        map[string]*file{},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// file 一个录制文件，对应一个操作
+type file struct {
+	Operation    string         `json:"operation"`
+	Interactions []*Interaction `json:"interactions"`
+
+	// used 回放时记录已经使用过的录制
+	used []bool
+}
+
+// Interaction 一次录制的请求与响应
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 录制的请求，Body 为规范化后的 JSON
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	// BodyText 非 JSON 请求体的原始内容
+	BodyText string `json:"body_text,omitempty"`
+}
+
+// Response 录制的响应
+type Response struct {
+	Status   int             `json:"status"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	recorded := t.newRequest(req, reqBody)
+	name := operationName(req)
+
+	if t.mode == ModeRecord {
+		return t.record(req, name, recorded)
+	}
+	return t.replay(req, name, recorded)
+}
+
+// record 转发请求并把交互追加到录制文件
+func (t *Transport) record(req *http.Request, name string, recorded Request) (*http.Response, error) {
+	resp, err := t.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &Interaction{Request: recorded, Response: Response{Status: resp.StatusCode, Header: t.scrubHeader(resp.Header)}}
+	it.Response.Body, it.Response.BodyText = t.normalize(respBody)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 本次录制第一次遇到该操作时覆盖旧文件
+	f, ok := t.files[name]
+	if !ok {
+		f = &file{Operation: name}
+		t.files[name] = f
+	}
+	f.Interactions = append(f.Interactions, it)
+	if err := t.save(name, f); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay 从录制文件中查找匹配的交互，优先使用尚未回放过的录制
+func (t *Transport) replay(req *http.Request, name string, recorded Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, err := t.load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	match := -1
+	for i, it := range f.Interactions {
+		if !it.Request.matches(recorded) {
+			continue
+		}
+		if !f.used[i] {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("%w: %s %s %s (operation %q)", ErrNoMatch, recorded.Method, recorded.target(), recorded.body(), name)
+	}
+	f.used[match] = true
+
+	it := f.Interactions[match]
+	body := []byte(it.Response.BodyText)
+	if len(it.Response.Body) > 0 {
+		body = it.Response.Body
+	}
+	header := it.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", it.Response.Status, http.StatusText(it.Response.Status)),
+		StatusCode:    it.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// load 读取录制文件，调用方需持有锁
+func (t *Transport) load(name string) (*file, error) {
+	if f, ok := t.files[name]; ok {
+		return f, nil
+	}
+
+	data, err := os.ReadFile(t.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no cassette for operation %q", ErrNoMatch, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := &file{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("cassette: decode %s: %w", t.path(name), err)
+	}
+	f.used = make([]bool, len(f.Interactions))
+	t.files[name] = f
+	return f, nil
+}
+
+// save 写入录制文件，调用方需持有锁
+func (t *Transport) save(name string, f *file) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path(name), append(data, '\n'), 0o644)
+}
+
+func (t *Transport) path(name string) string {
+	return filepath.Join(t.dir, name+".json")
+}
+
+// newRequest 把请求转换为规范化、脱敏后的录制形式
+func (t *Transport) newRequest(req *http.Request, body []byte) Request {
+	r := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+	}
+	r.Body, r.BodyText = t.normalize(body)
+	return r
+}
+
+// normalize 规范化 JSON 并隐藏敏感字段，非 JSON 内容原样作为文本返回
+func (t *Transport) normalize(body []byte) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, ""
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, string(body)
+	}
+
+	out, err := json.Marshal(t.scrub(v))
+	if err != nil {
+		return nil, string(body)
+	}
+	return out, ""
+}
+
+// scrub 递归隐藏 JSON 中的敏感字段
+func (t *Transport) scrub(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if t.scrubFields[strings.ToLower(k)] {
+				if s, ok := val.(string); ok && s != "" {
+					v[k] = redacted
+				}
+				continue
+			}
+			v[k] = t.scrub(val)
+		}
+	case []any:
+		for i := range v {
+			v[i] = t.scrub(v[i])
+		}
+	}
+	return v
+}
+
+// scrubHeader 去掉敏感响应头以及每次都会变化的 Date、Content-Length
+func (t *Transport) scrubHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for k, v := range h {
+		switch {
+		case t.scrubHeaders[k]:
+			out[k] = []string{redacted}
+		case k == "Date" || k == "Content-Length":
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// matches 比较方法、路径、查询参数与规范化后的请求体
+func (r Request) matches(other Request) bool {
+	return r.Method == other.Method &&
+		r.Path == other.Path &&
+		r.Query == other.Query &&
+		bytes.Equal(normalizeJSON(r.Body), normalizeJSON(other.Body)) &&
+		r.BodyText == other.BodyText
+}
+
+func (r Request) target() string {
+	if r.Query == "" {
+		return r.Path
+	}
+	return r.Path + "?" + r.Query
+}
+
+func (r Request) body() string {
+	if len(r.Body) > 0 {
+		return string(r.Body)
+	}
+	return r.BodyText
+}
+
+// normalizeJSON 消除录制文件缩进带来的差异
+func normalizeJSON(raw json.RawMessage) []byte {
+	if len(raw) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// operationName 从请求的 ctx 中取得 SDK 操作名，作为录制文件名
+func operationName(req *http.Request) string {
+	name := "unknown"
+	if op, ok := wukong.OperationFromContext(req.Context()); ok && op.Name != "" {
+		name = op.Name
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// readBody 读取并还原 body，使其可以再次被读取
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,164 @@
+package cassette_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/cassette"
+)
+
+const goldenUpdateToken = `{
+  "operation": "user.UpdateToken",
+  "interactions": [
+    {
+      "request": {
+        "method": "POST",
+        "path": "/user/token",
+        "body": {
+          "device_flag": 1,
+          "device_level": 0,
+          "token": "[REDACTED]",
+          "uid": "u1"
+        }
+      },
+      "response": {
+        "status": 200,
+        "header": {
+          "Content-Type": [
+            "application/json"
+          ],
+          "Token": [
+            "[REDACTED]"
+          ]
+        },
+        "body": {
+          "status": "ok"
+        }
+      }
+    }
+  ]
+}
+`
+
+// newUpstream 模拟真实服务，返回固定响应并记录收到的 Authorization 头
+func newUpstream(t *testing.T, auth *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Token", "server-secret")
+		_, _ = io.WriteString(w, `{"status": "ok"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newClient(baseURL string, rec *cassette.Transport) *wukong.Client {
+	return wukong.NewClient(wukong.Config{
+		BaseURL:   baseURL,
+		Token:     "api-secret",
+		Transport: rec,
+	})
+}
+
+func updateToken(cli *wukong.Client) error {
+	_, err := cli.User.UpdateToken(context.Background(), &wukong.UpdateUserTokenRequest{
+		UID:        "u1",
+		Token:      "user-secret",
+		DeviceFlag: 1,
+	})
+	return err
+}
+
+func TestRecordWritesScrubbedGoldenFile(t *testing.T) {
+	var auth string
+	srv := newUpstream(t, &auth)
+	dir := t.TempDir()
+
+	cli := newClient(srv.URL, cassette.New(dir, cassette.ModeRecord))
+	defer cli.Close()
+	if err := updateToken(cli); err != nil {
+		t.Fatalf("UpdateToken: %v", err)
+	}
+	if auth != "Bearer api-secret" {
+		t.Errorf("upstream Authorization = %q, want the real token", auth)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "user.UpdateToken.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != goldenUpdateToken {
+		t.Errorf("cassette =\n%s\nwant\n%s", data, goldenUpdateToken)
+	}
+	for _, secret := range []string{"api-secret", "user-secret", "server-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette leaks %q", secret)
+		}
+	}
+}
+
+func TestReplayRecordedInteraction(t *testing.T) {
+	var auth string
+	srv := newUpstream(t, &auth)
+	dir := t.TempDir()
+
+	rec := newClient(srv.URL, cassette.New(dir, cassette.ModeRecord))
+	if err := updateToken(rec); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	rec.Close()
+	srv.Close()
+
+	cli := newClient(srv.URL, cassette.New(dir, cassette.ModeReplay))
+	defer cli.Close()
+	var meta wukong.ResponseMeta
+	_, err := cli.User.UpdateToken(context.Background(), &wukong.UpdateUserTokenRequest{
+		UID:        "u1",
+		Token:      "user-secret",
+		DeviceFlag: 1,
+	}, wukong.WithResponseCapture(&meta))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if meta.StatusCode != http.StatusOK || meta.Header.Get("Token") != "[REDACTED]" {
+		t.Errorf("replayed response = %d %v", meta.StatusCode, meta.Header)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(meta.Body, &body); err != nil || body["status"] != "ok" {
+		t.Errorf("replayed body = %s, err %v", meta.Body, err)
+	}
+}
+
+func TestReplayNoMatch(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "user.UpdateToken.json")
+	if err := os.WriteFile(golden, []byte(goldenUpdateToken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cli := newClient("http://127.0.0.1:1", cassette.New(dir, cassette.ModeReplay))
+	defer cli.Close()
+
+	// 录制文件中的请求可以回放，字段顺序无关
+	if err := updateToken(cli); err != nil {
+		t.Fatalf("replay golden: %v", err)
+	}
+
+	_, err := cli.User.UpdateToken(context.Background(), &wukong.UpdateUserTokenRequest{UID: "u2", Token: "t"})
+	if !errors.Is(err, cassette.ErrNoMatch) {
+		t.Errorf("different body: err = %v, want ErrNoMatch", err)
+	}
+	_, err = cli.System.Health(context.Background())
+	if !errors.Is(err, cassette.ErrNoMatch) {
+		t.Errorf("operation without cassette: err = %v, want ErrNoMatch", err)
+	}
+}
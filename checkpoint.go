@@ -0,0 +1,178 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// CheckpointStore 持久化每个频道已经处理到的消息序号（水位），供 SeqTracker 使用
+// 实现需要支持并发调用
+type CheckpointStore interface {
+	// Load 返回频道的水位，没有记录时返回 0 和 nil
+	Load(ctx context.Context, channel ChannelKey) (int64, error)
+	// Save 保存频道的水位
+	Save(ctx context.Context, channel ChannelKey, seq int64) error
+}
+
+// MemoryCheckpointStore 基于内存的 CheckpointStore，进程退出后水位丢失，适合测试或无需持久化的场景
+type MemoryCheckpointStore struct {
+	mu   sync.RWMutex
+	seqs map[ChannelKey]int64
+}
+
+var _ CheckpointStore = (*MemoryCheckpointStore)(nil)
+
+// NewMemoryCheckpointStore 创建内存水位存储
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{seqs: map[ChannelKey]int64{}}
+}
+
+// Load 实现 CheckpointStore
+func (s *MemoryCheckpointStore) Load(_ context.Context, channel ChannelKey) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.seqs[channel], nil
+}
+
+// Save 实现 CheckpointStore
+func (s *MemoryCheckpointStore) Save(_ context.Context, channel ChannelKey, seq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seqs[channel] = seq
+	return nil
+}
+
+// FileCheckpointStore 把所有频道的水位保存在一个 JSON 文件中，key 为 "channel_id:channel_type"
+// 每次 Save 都会先写入并 fsync 临时文件再重命名，进程崩溃或掉电时不会留下损坏的文件
+type FileCheckpointStore struct {
+	path string
+
+	mu     sync.Mutex
+	seqs   map[string]int64
+	loaded bool
+}
+
+var _ CheckpointStore = (*FileCheckpointStore)(nil)
+
+// NewFileCheckpointStore 创建基于文件的水位存储，文件不存在时在第一次 Save 时创建
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load 实现 CheckpointStore
+func (s *FileCheckpointStore) Load(_ context.Context, channel ChannelKey) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+	return s.seqs[channel.String()], nil
+}
+
+// Save 实现 CheckpointStore
+func (s *FileCheckpointStore) Save(_ context.Context, channel ChannelKey, seq int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	key := channel.String()
+	prev, existed := s.seqs[key]
+	s.seqs[key] = seq
+	if err := s.flush(); err != nil {
+		// 写入失败时回滚内存中的水位，保持与文件一致
+		if existed {
+			s.seqs[key] = prev
+		} else {
+			delete(s.seqs, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// load 第一次使用时读取文件，调用方需持有锁
+func (s *FileCheckpointStore) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	s.seqs = map[string]int64{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("wukongim: read checkpoint: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.seqs); err != nil {
+			return fmt.Errorf("wukongim: decode checkpoint %s: %w", s.path, err)
+		}
+	}
+	s.loaded = true
+	return nil
+}
+
+// flush 原子地写入文件并落盘，调用方需持有锁
+func (s *FileCheckpointStore) flush() error {
+	data, err := json.MarshalIndent(s.seqs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	// 先把内容落盘再重命名，否则掉电后可能看到重命名后的空文件
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("wukongim: write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// syncDir 把目录项（重命名结果）落盘；Windows 不支持对目录 fsync，直接跳过
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		_ = d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,76 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileCheckpointStorePersists(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "checkpoint.json")
+	g1 := ChannelKey{ChannelID: "g1", ChannelType: ChannelTypeGroup}
+	u1 := ChannelKey{ChannelID: "u1", ChannelType: ChannelTypePerson}
+
+	s := NewFileCheckpointStore(path)
+	if err := s.Save(ctx, g1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, u1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, g1, 12); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := NewFileCheckpointStore(path)
+	for key, want := range map[ChannelKey]int64{g1: 12, u1: 3, {ChannelID: "g2", ChannelType: ChannelTypeGroup}: 0} {
+		got, err := reopened.Load(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Load(%s) = %d, want %d", key, got, want)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("checkpoint dir has %d entries, want only the checkpoint file", len(entries))
+	}
+}
+
+func TestSeqTrackerReleasesChannelLocks(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewSeqTracker(nil, SeqTrackerConfig{})
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch := fmt.Sprintf("g%d", i%5)
+			for seq := int64(1); seq <= 20; seq++ {
+				if _, err := tracker.Observe(ctx, Message{ChannelID: ch, ChannelType: ChannelTypeGroup, MessageSeq: seq}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	tracker.mu.Lock()
+	n := len(tracker.locks)
+	tracker.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d channel locks left after all calls returned, want 0", n)
+	}
+}
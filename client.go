@@ -2,8 +2,11 @@ package wukong_go_sdk
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"resty.dev/v3"
+	"sync"
 	"time"
 )
 
@@ -11,7 +14,7 @@ import (
 // BaseURL 例如：http://localhost:5001
 // Token / AppKey 等认证信息后续可按实际文档扩展
 // Timeout 为每次请求的超时时间
-// Debug 控制 resty 的调试输出
+// Debug 控制调试输出：未配置 Logger 时使用 resty 的调试输出，否则通过 Logger 输出请求/响应体
 type Config struct {
 	BaseURL string
 
@@ -21,6 +24,43 @@ type Config struct {
 
 	Timeout time.Duration
 	Debug   bool
+
+	// Transport 自定义底层 http.RoundTripper，例如 cassette 录制/回放传输层
+	// 与 WithTransport 等价，两者同时设置时以 WithTransport 为准
+	Transport http.RoundTripper
+
+	// Endpoints 集群中各节点的 HTTP API 地址，配置后优先于 BaseURL
+	// 例如 []string{"http://node1:5001", "http://node2:5001"}
+	Endpoints []string
+	// EndpointSelection 节点选择策略，默认轮询
+	EndpointSelection EndpointSelection
+	// EjectThreshold 节点连续失败（连接错误或 5xx）多少次后被摘除，默认 1
+	EjectThreshold int
+	// HealthCheckInterval 对被摘除节点发起健康探测的间隔，默认 5s
+	HealthCheckInterval time.Duration
+
+	// CircuitBreaker 熔断器配置，为 nil 时不开启
+	// 熔断器按节点划分，打开时请求会立即返回 ErrCircuitOpen
+	CircuitBreaker *CircuitBreakerConfig
+
+	// RateLimit 客户端限流与并发上限，为 nil 时不限制
+	// 服务端返回 429 时会按 Retry-After 暂停发放令牌
+	RateLimit *RateLimitConfig
+
+	// Retry 重试策略，为 nil 时不重试，可使用 DefaultRetryPolicy()
+	// 多节点时每次重试会优先切换到尚未尝试过的健康节点
+	Retry *RetryPolicy
+
+	// Middlewares 中间件，按顺序包裹每一次调用，也可以之后通过 Client.Use 追加
+	Middlewares []Middleware
+
+	// Metrics 指标收集器，为 nil 时不上报，例如 promwukong.NewCollector()
+	Metrics MetricsRecorder
+
+	// Logger 结构化日志，为 nil 时不输出；认证信息、Token、密码会被自动隐藏
+	Logger *slog.Logger
+	// LogOptions 控制日志级别、请求体输出与脱敏
+	LogOptions LogOptions
 }
 
 // Client 是 WuKongIM API 的客户端
@@ -29,6 +69,20 @@ type Client struct {
 	cfg *Config
 	cli *resty.Client
 
+	mu          sync.RWMutex
+	middlewares []Middleware
+	roundTrip   RoundTrip
+
+	pool      *endpointPool
+	breakers  *breakerSet
+	limiter   *limiter
+	closeCh   chan struct{}
+	closeOnce sync.Once
+
+	healthMu      sync.Mutex
+	healthRunning bool
+	healthWG      sync.WaitGroup
+
 	Route        *RouteService
 	Message      *MessageService
 	Channel      *ChannelService
@@ -41,8 +95,38 @@ type Client struct {
 }
 
 // NewClient 根据配置创建一个新的 WuKongIM 客户端
-func NewClient(cfg Config) *Client {
-	c := resty.New()
+// opts 可用于定制底层 HTTP 客户端，例如 WithTransport、WithTLSConfig
+// NewClient 不会校验配置，需要拿到校验错误时使用 NewClientE
+func NewClient(cfg Config, opts ...Option) *Client {
+	client, _ := newClient(cfg, opts)
+	return client
+}
+
+// NewClientE 与 NewClient 相同，但会校验配置
+// BaseURL / Endpoints 为空或格式错误、选项无法应用时返回错误
+func NewClientE(cfg Config, opts ...Option) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(cfg, opts)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newClient 创建客户端，返回的错误来自选项的应用，客户端本身始终可用
+func newClient(cfg Config, opts []Option) (*Client, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.transport == nil && cfg.Transport != nil {
+		o.transport = cfg.Transport
+	}
+	c := o.newRestyClient()
 
 	if cfg.BaseURL != "" {
 		c.SetBaseURL(cfg.BaseURL)
@@ -55,18 +139,53 @@ func NewClient(cfg Config) *Client {
 
 	c.SetHeader("Content-Type", "application/json")
 
+	optErr := o.apply(c)
+
 	if cfg.Debug {
-		c.SetDebug(true)
+		if cfg.Logger != nil {
+			// 配置了 Logger 时，调试信息改为通过 Logger 输出脱敏后的请求/响应体
+			cfg.LogOptions.LogBodies = true
+		} else {
+			c.SetDebug(true)
+			// 调试输出中隐藏 Authorization 等认证信息，按 LogOptions 隐藏消息 Payload
+			c.OnDebugLog(redactDebugLog(cfg.LogOptions))
+		}
 	}
 
 	// 配置全局错误反序列化结构
 	c.SetError(&APIError{})
+	// 保留响应体，便于在 APIError 中携带原始错误内容
+	c.SetResponseBodyUnlimitedReads(true)
 
 	client := &Client{
-		cfg: &cfg,
-		cli: c,
+		cfg:     &cfg,
+		cli:     c,
+		closeCh: make(chan struct{}),
 	}
 
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 && cfg.BaseURL != "" {
+		endpoints = []string{cfg.BaseURL}
+	}
+	if len(endpoints) > 0 {
+		client.pool = newEndpointPool(endpoints, cfg.EndpointSelection, cfg.EjectThreshold)
+	}
+	if cfg.CircuitBreaker != nil {
+		client.breakers = newBreakerSet(cfg.CircuitBreaker)
+	}
+	if cfg.RateLimit != nil {
+		client.limiter = newLimiter(cfg.RateLimit)
+	}
+
+	if cfg.Metrics != nil {
+		// 指标中间件位于最外层，统计完整的调用耗时
+		client.Use(metricsMiddleware(cfg.Metrics))
+	}
+	if cfg.Logger != nil {
+		client.Use(loggingMiddleware(cfg.Logger, cfg.LogOptions))
+	}
+	client.Use(cfg.Middlewares...)
+
 	// 统一设置认证（例如 Header 或 Query），具体根据 WuKongIM 文档调整
 	client.applyAuth()
 
@@ -81,7 +200,11 @@ func NewClient(cfg Config) *Client {
 	client.Manager = &ManagerService{client: client}
 	client.System = &SystemService{client: client}
 
-	return client
+	if cfg.HealthCheckInterval <= 0 {
+		cfg.HealthCheckInterval = 5 * time.Second
+	}
+
+	return client, optErr
 }
 
 // applyAuth 根据配置把认证信息挂到 resty 客户端
@@ -95,37 +218,232 @@ func (c *Client) applyAuth() {
 }
 
 // do 执行 HTTP 请求的公共封装
+// name: 操作名，例如 "message.SendMessage"
 // method: GET/POST/PUT/DELETE
-// path: 相对路径，例如 "/api/v1/message/send"
+// path: 相对路径，例如 "/api/v1/message/send"，不包含查询参数
+// query: 查询参数，由 resty 负责编码，可以为 nil
 // reqBody: 请求体结构体，会被编码为 JSON
 // respBody: 响应体结构体指针，用于 JSON 反序列化
-func (c *Client) do(ctx context.Context, method, path string, reqBody any, respBody any) (*resty.Response, error) {
+//
+// opts: 单次调用选项，例如超时、附加请求头、捕获响应元数据
+//
+// 请求会依次经过 Config.Middlewares 与 Client.Use 注册的中间件，最后由 transport 发出
+func (c *Client) do(ctx context.Context, name, method, path string, query url.Values, reqBody any, respBody any, opts ...CallOption) error {
+	c.mu.RLock()
+	roundTrip := c.roundTrip
+	c.mu.RUnlock()
+
+	o := newCallOptions(opts)
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	op := newOperation(name, method, path, reqBody, respBody)
+	op.Query = query
+	op.retry = c.cfg.Retry
+	if o.retrySet {
+		op.retry = o.retry
+	}
+	for k, v := range o.header {
+		op.Header[k] = append(op.Header[k], v...)
+	}
+
+	start := time.Now()
+	err := roundTrip(context.WithValue(ctx, operationCtxKey{}, op), op)
+
+	if o.capture != nil {
+		*o.capture = ResponseMeta{
+			StatusCode: op.StatusCode,
+			Latency:    time.Since(start),
+			Endpoint:   op.Endpoint,
+			Attempts:   op.Attempts,
+		}
+		if op.resp != nil {
+			o.capture.Header = op.resp.Header()
+			o.capture.Body = op.resp.Bytes()
+		}
+	}
+	return err
+}
+
+// transport 是中间件链最内层的 RoundTrip，负责实际发出请求
+// 配置了 Config.Retry 或 WithRetryPolicy 时，幂等请求会按策略重试，最终错误会被包装为 *RetryError
+func (c *Client) transport(ctx context.Context, op *Operation) error {
+	policy := op.retry
+	maxAttempts := 1
+	if idempotent(op.Method, op.Request) {
+		maxAttempts = policy.maxAttempts()
+	}
+
+	var (
+		err     error
+		attempt int
+		tried   []*endpoint
+	)
+	for attempt = 1; ; attempt++ {
+		ep, br, serr := c.selectEndpoint(op, tried)
+		if serr != nil {
+			serr = wrapError("client.do", serr)
+			if attempt == 1 {
+				// 熔断时请求没有发出，直接返回
+				return serr
+			}
+			// 重试时所有节点都已熔断，保留上一次尝试的错误
+			attempt--
+			break
+		}
+
+		url := op.Path
+		if ep != nil {
+			tried = append(tried, ep)
+			op.Endpoint = ep.url
+			url = ep.url + op.Path
+		}
+
+		release := func() {}
+		if c.limiter != nil {
+			var lerr error
+			if release, lerr = c.limiter.acquire(ctx, op.Name); lerr != nil {
+				// 等待限流期间 ctx 结束，请求没有发出
+				if br != nil {
+					br.record(outcomeIgnored, time.Now())
+				}
+				if attempt == 1 {
+					return wrapError("client.do", &TransportError{Method: op.Method, Path: op.Path, Err: lerr})
+				}
+				attempt--
+				break
+			}
+		}
+
+		var resp *resty.Response
+		start := time.Now()
+		resp, err = c.execute(ctx, op, url)
+		release()
+
+		op.Attempts = attempt
+		op.resp = resp
+		op.StatusCode = 0
+		if resp != nil {
+			op.StatusCode = resp.StatusCode()
+		}
+		// 节点与熔断器都按实际状态码记录，2xx 响应体解析失败（DecodeError）不计为失败
+		if ep != nil && c.pool.record(ctx, ep, op.StatusCode, time.Since(start), err) {
+			c.startHealthCheck()
+		}
+		if br != nil {
+			c.notifyCircuit(br.record(classifyOutcome(ctx, op.StatusCode, err), time.Now()))
+		}
+
+		var retryAfter time.Duration
+		if resp != nil && op.StatusCode == http.StatusTooManyRequests {
+			if d, ok := parseRetryAfter(resp.Header(), time.Now()); ok {
+				retryAfter = d
+				if c.limiter != nil {
+					c.limiter.throttle(op.Name, time.Now().Add(d))
+				}
+			}
+		}
+
+		retry := attempt < maxAttempts && ctx.Err() == nil && policy.shouldRetry(op.StatusCode, err)
+		var backoff time.Duration
+		if retry {
+			// 服务端要求的等待时间优先于本地退避
+			backoff = max(policy.backoff(attempt), retryAfter)
+		}
+
+		info := RetryAttempt{
+			Name:       op.Name,
+			Method:     op.Method,
+			Path:       op.Path,
+			Endpoint:   op.Endpoint,
+			Attempt:    attempt,
+			StatusCode: op.StatusCode,
+			Err:        err,
+			WillRetry:  retry,
+			Backoff:    backoff,
+		}
+		policy.observe(ctx, info)
+		if retry && c.cfg.Logger != nil {
+			logRetry(ctx, c.cfg.Logger, c.cfg.LogOptions, info)
+		}
+
+		if !retry || sleepContext(ctx, backoff) != nil {
+			break
+		}
+		if c.cfg.Metrics != nil {
+			c.cfg.Metrics.RetryAttempted(op.Name, op.StatusCode)
+		}
+	}
+
+	if err != nil && policy != nil {
+		return &RetryError{Attempts: attempt, Err: err}
+	}
+	return err
+}
+
+// execute 执行一次 HTTP 请求
+// url 为完整地址（多节点时）或相对路径（使用 resty 的 BaseURL）
+func (c *Client) execute(ctx context.Context, op *Operation, url string) (*resty.Response, error) {
 	req := c.cli.R().SetContext(ctx)
 
-	if reqBody != nil {
-		req.SetBody(reqBody)
+	if len(op.Header) > 0 {
+		req.SetHeaderMultiValues(op.Header)
 	}
 
-	if respBody != nil {
-		req.SetResult(respBody)
+	if len(op.Query) > 0 {
+		req.SetQueryParamsFromValues(op.Query)
 	}
 
-	resp, err := req.Execute(method, path)
+	if op.Request != nil {
+		req.SetBody(op.Request)
+	}
+
+	if op.Response != nil {
+		req.SetResult(op.Response)
+	}
+
+	resp, err := req.Execute(op.Method, url)
 	if err != nil {
-		return nil, wrapError("client.do", err)
+		if resp != nil && resp.IsError() {
+			// 错误响应体无法按 JSON 解析，保留原始内容
+			return resp, newAPIError(resp, op, nil)
+		}
+		if resp != nil && resp.StatusCode() != 0 {
+			// 拿到了成功响应但响应体无法读取或解析，保留响应，节点与熔断器按实际状态码记录
+			return resp, wrapError("client.do", &DecodeError{
+				Method:     op.Method,
+				Path:       op.Path,
+				StatusCode: resp.StatusCode(),
+				Body:       resp.Bytes(),
+				Err:        err,
+			})
+		}
+		return nil, wrapError("client.do", &TransportError{Method: op.Method, Path: op.Path, Err: err})
 	}
 
 	if resp.IsError() {
-		if errObj, ok := resp.Error().(*APIError); ok {
-			errObj.HttpCode = resp.StatusCode()
-			return resp, errObj
-		}
-		return resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		errObj, _ := resp.Error().(*APIError)
+		return resp, newAPIError(resp, op, errObj)
 	}
 
 	return resp, nil
 }
 
+// newAPIError 用响应信息补全 APIError，errObj 为 nil 时（例如非 JSON 的错误响应）新建一个
+func newAPIError(resp *resty.Response, op *Operation, errObj *APIError) *APIError {
+	if errObj == nil {
+		errObj = &APIError{}
+	}
+	errObj.HttpCode = resp.StatusCode()
+	errObj.Method = op.Method
+	errObj.Path = op.Path
+	errObj.Body = resp.Bytes()
+	return errObj
+}
+
 //func HttpCodeError(code int) error {
 //	switch code {
 //	case http.StatusOK:
@@ -0,0 +1,26 @@
+package wukong_go_sdk
+
+import "github.com/linabellbiu/wukong-go-sdk/payload"
+
+// SetContent 把类型化的消息内容编码为 base64 并写入 Payload
+//
+//	req := &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "u2", ChannelType: wukong.ChannelTypePerson}
+//	_ = req.SetContent(&payload.Text{Content: "hi"})
+func (r *SendMessageRequest) SetContent(c payload.Content) error {
+	p, err := payload.Encode(c)
+	if err != nil {
+		return err
+	}
+	r.Payload = p
+	return nil
+}
+
+// Content 解码 Payload 为类型化的消息内容，未注册的类型返回 payload.Generic
+func (m *Message) Content() (payload.Content, error) {
+	return payload.Decode(m.Payload)
+}
+
+// Content 解码 Payload 为类型化的消息内容，未注册的类型返回 payload.Generic
+func (m *ConversationRecentMessage) Content() (payload.Content, error) {
+	return payload.Decode(m.Payload)
+}
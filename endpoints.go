@@ -0,0 +1,295 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointSelection 多节点时选择请求节点的策略
+type EndpointSelection int
+
+const (
+	// SelectRoundRobin 在健康节点之间轮询
+	SelectRoundRobin EndpointSelection = 0
+	// SelectLeastLatency 选择平均延迟最低的健康节点
+	SelectLeastLatency EndpointSelection = 1
+)
+
+// EndpointStatus 描述一个节点的当前状态，由 Client.Endpoints 返回
+type EndpointStatus struct {
+	URL string
+	// Healthy 为 false 表示节点已被摘除，等待主动探测恢复
+	Healthy bool
+	// Latency 最近请求延迟的指数加权平均值
+	Latency time.Duration
+	// ConsecutiveFailures 连续失败次数
+	ConsecutiveFailures int
+	// Requests / Failures 累计请求数与失败数
+	Requests int64
+	Failures int64
+	// LastError 最近一次失败的错误信息
+	LastError string
+	// EjectedAt 节点被摘除的时间
+	EjectedAt time.Time
+}
+
+// endpoint 单个节点的运行状态
+type endpoint struct {
+	url string
+
+	mu                  sync.Mutex
+	healthy             bool
+	latency             time.Duration
+	consecutiveFailures int
+	requests            int64
+	failures            int64
+	lastError           string
+	ejectedAt           time.Time
+}
+
+// latencyWeight 延迟指数加权平均中新样本的权重
+const latencyWeight = 0.3
+
+func (e *endpoint) isHealthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy
+}
+
+func (e *endpoint) avgLatency() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.latency
+}
+
+func (e *endpoint) recordSuccess(elapsed time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.consecutiveFailures = 0
+	e.healthy = true
+	if e.latency == 0 {
+		e.latency = elapsed
+	} else {
+		e.latency = time.Duration(latencyWeight*float64(elapsed) + (1-latencyWeight)*float64(e.latency))
+	}
+}
+
+// recordFailure 记录一次失败，连续失败达到 threshold 时摘除节点，返回节点是否因此被摘除
+func (e *endpoint) recordFailure(err error, threshold int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failures++
+	e.consecutiveFailures++
+	e.lastError = err.Error()
+	if e.healthy && e.consecutiveFailures >= threshold {
+		e.healthy = false
+		e.ejectedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return EndpointStatus{
+		URL:                 e.url,
+		Healthy:             e.healthy,
+		Latency:             e.latency,
+		ConsecutiveFailures: e.consecutiveFailures,
+		Requests:            e.requests,
+		Failures:            e.failures,
+		LastError:           e.lastError,
+		EjectedAt:           e.ejectedAt,
+	}
+}
+
+// endpointPool 管理多个节点，负责选择、摘除与恢复
+type endpointPool struct {
+	endpoints []*endpoint
+	selection EndpointSelection
+	threshold int
+	next      atomic.Uint64
+}
+
+func newEndpointPool(urls []string, selection EndpointSelection, threshold int) *endpointPool {
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	p := &endpointPool{selection: selection, threshold: threshold}
+	for _, u := range urls {
+		p.endpoints = append(p.endpoints, &endpoint{url: strings.TrimRight(u, "/"), healthy: true})
+	}
+	return p
+}
+
+// pick 选择一个节点，优先选择健康且本次调用尚未尝试过的节点
+// 所有节点都不可用时仍然返回一个节点，避免在全部摘除时完全无法请求
+func (p *endpointPool) pick(tried []*endpoint) *endpoint {
+	if len(p.endpoints) == 1 {
+		return p.endpoints[0]
+	}
+
+	untried := func(e *endpoint) bool {
+		for _, t := range tried {
+			if t == e {
+				return false
+			}
+		}
+		return true
+	}
+
+	var candidates []*endpoint
+	for _, e := range p.endpoints {
+		if e.isHealthy() && untried(e) {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, e := range p.endpoints {
+			if untried(e) {
+				candidates = append(candidates, e)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = p.endpoints
+	}
+
+	if p.selection == SelectLeastLatency {
+		best := candidates[0]
+		for _, e := range candidates[1:] {
+			if e.avgLatency() < best.avgLatency() {
+				best = e
+			}
+		}
+		return best
+	}
+
+	n := p.next.Add(1) - 1
+	return candidates[n%uint64(len(candidates))]
+}
+
+// record 根据一次尝试的结果更新节点状态，返回节点是否因此被摘除
+// 传输层错误与 5xx 视为节点故障，4xx 属于业务错误，不影响节点健康
+func (p *endpointPool) record(ctx context.Context, e *endpoint, statusCode int, elapsed time.Duration, err error) bool {
+	switch classifyOutcome(ctx, statusCode, err) {
+	case outcomeSuccess:
+		e.recordSuccess(elapsed)
+	case outcomeFailure:
+		if len(p.endpoints) > 1 {
+			return e.recordFailure(err, p.threshold)
+		}
+	}
+	return false
+}
+
+func (p *endpointPool) ejected() []*endpoint {
+	var out []*endpoint
+	for _, e := range p.endpoints {
+		if !e.isHealthy() {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Endpoints 返回所有节点的当前状态
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.pool == nil {
+		return nil
+	}
+
+	out := make([]EndpointStatus, 0, len(c.pool.endpoints))
+	for _, e := range c.pool.endpoints {
+		out = append(out, e.status())
+	}
+	return out
+}
+
+// startHealthCheck 在有节点被摘除时启动后台探测，已经在运行或 Client 已关闭时什么也不做
+func (c *Client) startHealthCheck() {
+	c.healthMu.Lock()
+	defer c.healthMu.Unlock()
+
+	if c.healthRunning {
+		return
+	}
+	select {
+	case <-c.closeCh:
+		return
+	default:
+	}
+
+	c.healthRunning = true
+	c.healthWG.Add(1)
+	go c.healthCheckLoop(c.cfg.HealthCheckInterval)
+}
+
+// healthCheckLoop 定期探测被摘除的节点，成功后恢复；所有节点都恢复后退出，下次有节点被摘除时重新启动
+func (c *Client) healthCheckLoop(interval time.Duration) {
+	defer c.healthWG.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		case <-ticker.C:
+			for _, e := range c.pool.ejected() {
+				c.probe(e, interval)
+			}
+		}
+
+		// 持有锁检查，保证与 startHealthCheck 之间不会漏掉新摘除的节点
+		c.healthMu.Lock()
+		if len(c.pool.ejected()) == 0 {
+			c.healthRunning = false
+			c.healthMu.Unlock()
+			return
+		}
+		c.healthMu.Unlock()
+	}
+}
+
+// probe 对单个节点发起一次 GET /health
+// 探测直接通过底层 HTTP 客户端发出，不经过中间件、限流、熔断与重试，也不会计入指标和日志
+func (c *Client) probe(e *endpoint, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.cli.R().SetContext(ctx).Get(e.url + "/health")
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode()
+		if err == nil && resp.IsError() {
+			err = fmt.Errorf("health check: %s", resp.Status())
+		}
+	}
+	c.pool.record(ctx, e, statusCode, time.Since(start), err)
+}
+
+// Close 停止后台的节点健康探测，之后 Client 仍然可以继续发起请求
+// 后台探测只在有节点被摘除时运行，并在所有节点恢复后自动退出，因此不调用 Close 也不会一直占用 goroutine
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.healthMu.Lock()
+		close(c.closeCh)
+		c.healthMu.Unlock()
+		c.healthWG.Wait()
+	})
+	return nil
+}
@@ -0,0 +1,218 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testNode 可以切换健康状态的 httptest 节点
+type testNode struct {
+	*httptest.Server
+	down     atomic.Bool
+	requests atomic.Int64
+	health   atomic.Int64
+}
+
+func newTestNode(t *testing.T) *testNode {
+	t.Helper()
+	n := &testNode{}
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			n.health.Add(1)
+		} else {
+			n.requests.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if n.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, `{"status":503,"msg":"unavailable"}`)
+			return
+		}
+		if r.URL.Path == "/channel/info" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"status":400,"msg":"bad request"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func endpointStatus(t *testing.T, cli *Client, url string) EndpointStatus {
+	t.Helper()
+	for _, s := range cli.Endpoints() {
+		if s.URL == url {
+			return s
+		}
+	}
+	t.Fatalf("endpoint %s not found", url)
+	return EndpointStatus{}
+}
+
+func TestEndpointFailover(t *testing.T) {
+	good, bad := newTestNode(t), newTestNode(t)
+	bad.down.Store(true)
+
+	cli := NewClient(Config{
+		Endpoints:           []string{bad.URL, good.URL},
+		HealthCheckInterval: time.Hour,
+		Retry:               &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	defer cli.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := cli.System.Health(ctx); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+
+	if s := endpointStatus(t, cli, bad.URL); s.Healthy || s.Failures != 1 {
+		t.Errorf("bad endpoint = %+v, want ejected after one failure", s)
+	}
+	if s := endpointStatus(t, cli, good.URL); !s.Healthy || s.Failures != 0 {
+		t.Errorf("good endpoint = %+v, want healthy", s)
+	}
+	// 摘除后不再向故障节点发送请求
+	if n := bad.health.Load(); n != 1 {
+		t.Errorf("bad endpoint received %d requests, want 1", n)
+	}
+}
+
+func TestEndpointClientErrorsDoNotEject(t *testing.T) {
+	a, b := newTestNode(t), newTestNode(t)
+	cli := NewClient(Config{Endpoints: []string{a.URL, b.URL}, HealthCheckInterval: time.Hour})
+	defer cli.Close()
+
+	for i := 0; i < 4; i++ {
+		_, err := cli.Channel.UpdateInfo(context.Background(), &UpdateInfoRequest{ChannelID: "g1", ChannelType: ChannelTypeGroup})
+		if err == nil {
+			t.Fatal("expected a 400 error")
+		}
+	}
+	for _, s := range cli.Endpoints() {
+		if !s.Healthy || s.Failures != 0 {
+			t.Errorf("endpoint %+v, want healthy after 4xx responses", s)
+		}
+	}
+}
+
+// newGarbledNode 返回 200 但响应体不是合法 JSON 的节点
+func newGarbledNode(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"status":`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestEndpointDecodeErrorsDoNotEject(t *testing.T) {
+	a, aHits := newGarbledNode(t)
+	b, bHits := newGarbledNode(t)
+	cli := NewClient(Config{
+		Endpoints:           []string{a.URL, b.URL},
+		HealthCheckInterval: time.Hour,
+		Retry:               &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	defer cli.Close()
+
+	for i := 0; i < 4; i++ {
+		var meta ResponseMeta
+		_, err := cli.System.Health(context.Background(), WithResponseCapture(&meta))
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("call %d: err = %v, want *DecodeError", i, err)
+		}
+		if decodeErr.StatusCode != http.StatusOK || string(decodeErr.Body) != `{"status":` || decodeErr.Path != "/health" {
+			t.Errorf("call %d: DecodeError = %+v", i, decodeErr)
+		}
+		var transportErr *TransportError
+		if errors.As(err, &transportErr) || IsRetryable(err) || errors.Is(err, ErrServerUnavailable) {
+			t.Errorf("call %d: decode error %v classified as a transport failure", i, err)
+		}
+		if meta.StatusCode != http.StatusOK || meta.Attempts != 1 {
+			t.Errorf("call %d: meta status = %d attempts = %d, want 200 after one attempt", i, meta.StatusCode, meta.Attempts)
+		}
+	}
+
+	if got := aHits.Load() + bHits.Load(); got != 4 {
+		t.Errorf("servers received %d requests, want 4 without retries", got)
+	}
+	for _, s := range cli.Endpoints() {
+		if !s.Healthy || s.Failures != 0 {
+			t.Errorf("endpoint %+v, want healthy after decode errors", s)
+		}
+	}
+}
+
+func TestEndpointHealthCheckRestores(t *testing.T) {
+	good, bad := newTestNode(t), newTestNode(t)
+	bad.down.Store(true)
+
+	var calls atomic.Int64
+	cli := NewClient(Config{
+		Endpoints:           []string{bad.URL, good.URL},
+		HealthCheckInterval: 10 * time.Millisecond,
+		Middlewares: []Middleware{func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, op *Operation) error {
+				calls.Add(1)
+				return next(ctx, op)
+			}
+		}},
+	})
+	defer cli.Close()
+
+	cli.healthMu.Lock()
+	running := cli.healthRunning
+	cli.healthMu.Unlock()
+	if running {
+		t.Fatal("health check started before any endpoint was ejected")
+	}
+
+	// 第一次请求落在故障节点上并将其摘除
+	if _, err := cli.System.Health(context.Background()); err == nil {
+		t.Fatal("expected the first call to fail")
+	}
+	if endpointStatus(t, cli, bad.URL).Healthy {
+		t.Fatal("bad endpoint was not ejected")
+	}
+
+	waitFor(t, func() bool { return bad.health.Load() >= 3 })
+	bad.down.Store(false)
+	waitFor(t, func() bool { return endpointStatus(t, cli, bad.URL).Healthy })
+
+	// 所有节点恢复后探测自动退出
+	waitFor(t, func() bool {
+		cli.healthMu.Lock()
+		defer cli.healthMu.Unlock()
+		return !cli.healthRunning
+	})
+
+	// 探测不经过中间件
+	if n := calls.Load(); n != 1 {
+		t.Errorf("middleware saw %d calls, want 1", n)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 2s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -1,6 +1,35 @@
 package wukong_go_sdk
 
-import pkgerrors "github.com/pkg/errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// 哨兵错误，APIError 与传输层错误都可以通过 errors.Is 与之匹配，
+// 即使经过 wrapError 包装也不受影响：
+//
+//	if errors.Is(err, wukong.ErrNotFound) { ... }
+var (
+	// ErrBadRequest 请求参数错误（400）
+	ErrBadRequest = errors.New("wukongim: bad request")
+	// ErrUnauthorized 未认证或 Token 无效（401）
+	ErrUnauthorized = errors.New("wukongim: unauthorized")
+	// ErrForbidden 没有权限（403）
+	ErrForbidden = errors.New("wukongim: forbidden")
+	// ErrNotFound 资源不存在（404）
+	ErrNotFound = errors.New("wukongim: not found")
+	// ErrRateLimited 请求过于频繁（429）
+	ErrRateLimited = errors.New("wukongim: rate limited")
+	// ErrServerUnavailable 服务端内部错误或不可用（5xx、连接被拒绝/重置）
+	ErrServerUnavailable = errors.New("wukongim: server unavailable")
+	// ErrTimeout 请求超时（408、504、网络超时、ctx 超时）
+	ErrTimeout = errors.New("wukongim: timeout")
+)
 
 // APIError 表示 WuKongIM REST 接口的标准错误响应
 // 参考文档示例：{"msg": "channel_id 参数不能为空", "status": 400}
@@ -10,6 +39,12 @@ type APIError struct {
 	Status        int    `json:"status"`
 	HttpCodeError string `json:"http_code_error"`
 	HttpCode      int    `json:"http_code"`
+
+	// 以下字段由 SDK 填充，便于排查问题
+	Method string `json:"-"`
+	Path   string `json:"-"`
+	// Body 服务端返回的原始响应体，非 JSON 的错误响应也会保留在这里
+	Body []byte `json:"-"`
 }
 
 func (e *APIError) Error() string {
@@ -25,9 +60,153 @@ func (e *APIError) Error() string {
 		return e.Message
 	}
 
+	if e.HttpCode != 0 {
+		return fmt.Sprintf("wukongim api error: unexpected status code %d", e.HttpCode)
+	}
+
 	return "wukongim api error"
 }
 
+// code 返回用于分类的状态码，优先使用 HTTP 状态码
+func (e *APIError) code() int {
+	if e.HttpCode != 0 {
+		return e.HttpCode
+	}
+	return e.Status
+}
+
+// Is 让 APIError 可以通过 errors.Is 匹配哨兵错误
+func (e *APIError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+
+	code := e.code()
+	switch target {
+	case ErrBadRequest:
+		return code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return code == http.StatusUnauthorized
+	case ErrForbidden:
+		return code == http.StatusForbidden
+	case ErrNotFound:
+		return code == http.StatusNotFound
+	case ErrRateLimited:
+		return code == http.StatusTooManyRequests
+	case ErrTimeout:
+		return code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout
+	case ErrServerUnavailable:
+		return code >= http.StatusInternalServerError && code != http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// Temporary 表示错误是否为临时性的（限流、超时、5xx）
+func (e *APIError) Temporary() bool {
+	if e == nil {
+		return false
+	}
+
+	code := e.code()
+	return code == http.StatusRequestTimeout ||
+		code == http.StatusTooManyRequests ||
+		code >= http.StatusInternalServerError
+}
+
+// Retryable 表示请求是否可以安全地原样重试
+// 与 Temporary 不同，500 可能已经在服务端产生了副作用，因此不视为可重试
+func (e *APIError) Retryable() bool {
+	if e == nil {
+		return false
+	}
+
+	switch e.code() {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// TransportError 表示请求没有拿到 HTTP 响应的传输层错误，
+// 例如连接被拒绝、连接重置、DNS 失败或超时
+type TransportError struct {
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Method, e.Path, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Is 让 TransportError 可以通过 errors.Is 匹配 ErrTimeout / ErrServerUnavailable
+func (e *TransportError) Is(target error) bool {
+	switch target {
+	case ErrTimeout:
+		return e.timeout()
+	case ErrServerUnavailable:
+		return !e.timeout() && isRetryableNetworkError(e.Err)
+	}
+	return false
+}
+
+// Temporary 表示错误是否为临时性的网络故障
+func (e *TransportError) Temporary() bool {
+	return isRetryableNetworkError(e.Err)
+}
+
+// Retryable 表示请求是否可以重试，调用方主动取消的请求不可重试
+func (e *TransportError) Retryable() bool {
+	return isRetryableNetworkError(e.Err)
+}
+
+func (e *TransportError) timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// DecodeError 表示服务端返回了成功的响应，但响应体无法读取或解析
+// 请求已经被服务端处理，因此既不是传输层错误，也不会被重试，节点同样不会因此被摘除
+type DecodeError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Body 服务端返回的原始响应体
+	Body []byte
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s %s: decode %d response: %v", e.Method, e.Path, e.StatusCode, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// IsTemporary 判断 err 链中是否有临时性错误
+func IsTemporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// IsRetryable 判断 err 链中的错误是否可以安全重试
+func IsRetryable(err error) bool {
+	var r interface{ Retryable() bool }
+	return errors.As(err, &r) && r.Retryable()
+}
+
 // wrapError 统一包装底层错误，带上模块关键字和操作名
 func wrapError(op string, err error) error {
 	if err == nil {
@@ -0,0 +1,194 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+var sentinels = []error{
+	ErrBadRequest,
+	ErrUnauthorized,
+	ErrForbidden,
+	ErrNotFound,
+	ErrRateLimited,
+	ErrServerUnavailable,
+	ErrTimeout,
+}
+
+// wrappings 依次模拟服务方法返回错误时经过的各层包装
+var wrappings = []struct {
+	name string
+	wrap func(error) error
+}{
+	{"bare", func(err error) error { return err }},
+	{"wrapError", func(err error) error { return wrapError("message.SendMessage", wrapError("client.do", err)) }},
+	{"RetryError", func(err error) error { return wrapError("message.SendMessage", &RetryError{Attempts: 3, Err: err}) }},
+}
+
+// checkSentinels 断言 err 只匹配 want 中的哨兵错误
+func checkSentinels(t *testing.T, err error, want ...error) {
+	t.Helper()
+	for _, s := range sentinels {
+		matched := errors.Is(err, s)
+		expected := false
+		for _, w := range want {
+			expected = expected || w == s
+		}
+		if matched != expected {
+			t.Errorf("errors.Is(%v, %v) = %v, want %v", err, s, matched, expected)
+		}
+	}
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	tests := []struct {
+		code      int
+		want      error
+		temporary bool
+		retryable bool
+	}{
+		{400, ErrBadRequest, false, false},
+		{401, ErrUnauthorized, false, false},
+		{403, ErrForbidden, false, false},
+		{404, ErrNotFound, false, false},
+		{408, ErrTimeout, true, true},
+		{409, nil, false, false},
+		{429, ErrRateLimited, true, true},
+		{500, ErrServerUnavailable, true, false},
+		{502, ErrServerUnavailable, true, true},
+		{503, ErrServerUnavailable, true, true},
+		{504, ErrTimeout, true, true},
+	}
+	for _, tt := range tests {
+		for _, w := range wrappings {
+			t.Run(fmt.Sprintf("%d/%s", tt.code, w.name), func(t *testing.T) {
+				err := w.wrap(&APIError{HttpCode: tt.code, Msg: "boom"})
+				if tt.want != nil {
+					checkSentinels(t, err, tt.want)
+				} else {
+					checkSentinels(t, err)
+				}
+
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.HttpCode != tt.code {
+					t.Errorf("errors.As = %v, want APIError %d", apiErr, tt.code)
+				}
+				if got := IsTemporary(err); got != tt.temporary {
+					t.Errorf("IsTemporary = %v, want %v", got, tt.temporary)
+				}
+				if got := IsRetryable(err); got != tt.retryable {
+					t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+				}
+			})
+		}
+	}
+}
+
+func TestAPIErrorCode(t *testing.T) {
+	// 没有 HTTP 状态码时使用响应体中的 status
+	checkSentinels(t, &APIError{Status: 404}, ErrNotFound)
+	// HTTP 状态码优先
+	checkSentinels(t, &APIError{Status: 400, HttpCode: 503}, ErrServerUnavailable)
+
+	var nilErr *APIError
+	if nilErr.Is(ErrNotFound) || nilErr.Temporary() || nilErr.Retryable() {
+		t.Error("nil APIError matched")
+	}
+	if (&APIError{HttpCode: 404}).Is(errors.New("other")) {
+		t.Error("APIError matched an unrelated error")
+	}
+}
+
+// timeoutError 模拟超时的 net.Error
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTransportErrorSentinels(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      error
+		retryable bool
+	}{
+		{"deadline", context.DeadlineExceeded, ErrTimeout, true},
+		{"net timeout", timeoutError{}, ErrTimeout, true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), ErrServerUnavailable, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrServerUnavailable, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, ErrServerUnavailable, true},
+		{"canceled", context.Canceled, nil, false},
+		{"unknown", errors.New("tls: bad certificate"), nil, false},
+	}
+	for _, tt := range tests {
+		for _, w := range wrappings {
+			t.Run(tt.name+"/"+w.name, func(t *testing.T) {
+				err := w.wrap(&TransportError{Method: http.MethodGet, Path: "/health", Err: tt.err})
+				if tt.want != nil {
+					checkSentinels(t, err, tt.want)
+				} else {
+					checkSentinels(t, err)
+				}
+
+				if !errors.Is(err, tt.err) {
+					t.Errorf("errors.Is(%v, %v) = false, want the cause to stay reachable", err, tt.err)
+				}
+				var te *TransportError
+				if !errors.As(err, &te) || te.Path != "/health" {
+					t.Errorf("errors.As = %v, want TransportError", te)
+				}
+				if got := IsRetryable(err); got != tt.retryable {
+					t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+				}
+				if got := IsTemporary(err); got != tt.retryable {
+					t.Errorf("IsTemporary = %v, want %v", got, tt.retryable)
+				}
+			})
+		}
+	}
+
+	if IsTemporary(errors.New("plain")) || IsRetryable(nil) {
+		t.Error("plain errors reported as temporary or retryable")
+	}
+}
+
+func TestClientErrorSentinels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code, _ := strconv.Atoi(r.URL.Query().Get("code"))
+		w.WriteHeader(code)
+		_, _ = io.WriteString(w, "plain text error")
+	}))
+	defer srv.Close()
+
+	cli := NewClient(Config{BaseURL: srv.URL})
+	defer cli.Close()
+
+	tests := []struct {
+		code int
+		want error
+	}{
+		{400, ErrBadRequest},
+		{404, ErrNotFound},
+		{429, ErrRateLimited},
+		{503, ErrServerUnavailable},
+		{504, ErrTimeout},
+	}
+	for _, tt := range tests {
+		err := cli.do(context.Background(), "system.Test", http.MethodGet, "/test",
+			map[string][]string{"code": {strconv.Itoa(tt.code)}}, nil, nil)
+		checkSentinels(t, err, tt.want)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || string(apiErr.Body) != "plain text error" || apiErr.Path != "/test" {
+			t.Errorf("%d: APIError = %+v, want raw body and path", tt.code, apiErr)
+		}
+	}
+}
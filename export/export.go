@@ -0,0 +1,210 @@
+// Package export 把频道的完整历史消息流式导出为 JSONL 或 CSV
+//
+// Exporter 从 seq 1 开始按页拉取到 GetMaxMessageSeq，逐条解码 Payload 后交给 Writer，
+// 任意时刻只在内存中保留一页消息；配合 wukong.CheckpointStore 可以在中断后从断点继续：
+//
+//	f, _ := os.OpenFile("g1.jsonl", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+//	defer f.Close()
+//
+//	exp := export.New(cli.Message, export.NewJSONLWriter(f),
+//		export.WithCheckpoint(wukong.NewFileCheckpointStore("g1.checkpoint.json")),
+//		export.WithSince(time.Now().AddDate(0, -6, 0)),
+//	)
+//	stats, err := exp.Export(ctx, wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup})
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// defaultPageSize 每次拉取的条数，同时也是保存断点的间隔
+const defaultPageSize = 100
+
+// Option 配置 Exporter
+type Option func(*Exporter)
+
+// WithLoginUID 导出个人频道时使用的登录用户，频道 ID 为对方 UID
+// 断点按频道保存，以不同登录用户导出同一个对方 UID 时需要使用不同的 CheckpointStore
+func WithLoginUID(uid string) Option {
+	return func(e *Exporter) {
+		e.loginUID = uid
+	}
+}
+
+// WithPageSize 设置每次拉取的条数，默认 100，每导出一页保存一次断点
+func WithPageSize(n int) Option {
+	return func(e *Exporter) {
+		e.pageSize = n
+	}
+}
+
+// WithCheckpoint 使用 store 记录每个频道已经导出到的 seq，再次导出时从断点之后继续
+// 断点只会在 Writer.Flush 成功之后保存，因此中断时最多重复导出一页
+func WithCheckpoint(store wukong.CheckpointStore) Option {
+	return func(e *Exporter) {
+		e.checkpoint = store
+	}
+}
+
+// WithSince 只导出 Timestamp 不早于 t 的消息
+func WithSince(t time.Time) Option {
+	return func(e *Exporter) {
+		e.since = t
+	}
+}
+
+// WithUntil 只导出 Timestamp 早于 t 的消息
+// 频道内 seq 越大消息越新，遇到第一条不早于 t 的消息即停止拉取，断点保存在这条消息之前
+func WithUntil(t time.Time) Option {
+	return func(e *Exporter) {
+		e.until = t
+	}
+}
+
+// Exporter 把频道历史消息写入 Writer
+type Exporter struct {
+	api        wukong.MessageAPI
+	w          Writer
+	loginUID   string
+	pageSize   int
+	checkpoint wukong.CheckpointStore
+	since      time.Time
+	until      time.Time
+}
+
+// New 创建 Exporter，api 通常为 Client.Message
+func New(api wukong.MessageAPI, w Writer, opts ...Option) *Exporter {
+	e := &Exporter{api: api, w: w}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.pageSize <= 0 {
+		e.pageSize = defaultPageSize
+	}
+	return e
+}
+
+// ChannelStats 单个频道的导出结果
+type ChannelStats struct {
+	Channel wukong.ChannelKey
+	// StartSeq / EndSeq 本次扫描的 seq 范围，StartSeq > EndSeq 表示没有新消息
+	// 设置了 Until 时 EndSeq 在第一条不早于 Until 的消息之前截止
+	StartSeq int64
+	EndSeq   int64
+	// Exported 写入 Writer 的消息数，不包括被时间范围过滤掉的消息
+	Exported int
+	// Skipped 被 Since 过滤掉的消息数
+	Skipped int
+}
+
+// Export 依次导出多个频道，遇到错误时停止并返回已经完成的频道以及出错频道的统计
+func (e *Exporter) Export(ctx context.Context, channels ...wukong.ChannelKey) ([]ChannelStats, error) {
+	out := make([]ChannelStats, 0, len(channels))
+	for _, ch := range channels {
+		stats, err := e.ExportChannel(ctx, ch)
+		out = append(out, stats)
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// ExportChannel 导出单个频道从断点（默认 seq 1）到当前最大 seq 的消息
+func (e *Exporter) ExportChannel(ctx context.Context, ch wukong.ChannelKey) (ChannelStats, error) {
+	stats := ChannelStats{Channel: ch, StartSeq: 1}
+	if e.checkpoint != nil {
+		seq, err := e.checkpoint.Load(ctx, ch)
+		if err != nil {
+			return stats, fmt.Errorf("export: channel %s: load checkpoint: %w", ch, err)
+		}
+		stats.StartSeq = seq + 1
+	}
+
+	resp, err := e.api.GetMaxMessageSeq(ctx, &wukong.MaxMessageSeqRequest{LoginUID: e.loginUID, ChannelID: ch.ChannelID, ChannelType: ch.ChannelType})
+	if err != nil {
+		return stats, fmt.Errorf("export: channel %s: %w", ch, err)
+	}
+	stats.EndSeq = resp.MaxMessageSeq
+	if stats.StartSeq > stats.EndSeq {
+		return stats, nil
+	}
+
+	hist := wukong.HistoryOptions{
+		LoginUID:  e.loginUID,
+		Direction: wukong.PullModeUp,
+		StartSeq:  stats.StartSeq,
+		EndSeq:    stats.EndSeq,
+		PageSize:  e.pageSize,
+	}
+	var last int64
+	pending := 0
+	for m, err := range wukong.IterateHistory(ctx, e.api, ch, hist) {
+		if err != nil {
+			// 保存已经写出的部分，下次从这里继续
+			if last > 0 {
+				if cpErr := e.commit(ctx, ch, last); cpErr != nil {
+					return stats, fmt.Errorf("export: channel %s: %w (also %v)", ch, err, cpErr)
+				}
+			}
+			return stats, fmt.Errorf("export: channel %s: %w", ch, err)
+		}
+
+		if e.after(m) {
+			// 之后的消息都晚于 Until，下次（例如放宽 Until 后）从这条消息继续
+			stats.EndSeq = m.MessageSeq - 1
+			break
+		}
+
+		last = m.MessageSeq
+		if e.before(m) {
+			stats.Skipped++
+		} else {
+			if err := e.w.Write(NewRecord(m)); err != nil {
+				return stats, fmt.Errorf("export: channel %s: write seq %d: %w", ch, m.MessageSeq, err)
+			}
+			stats.Exported++
+		}
+
+		if pending++; pending >= e.pageSize {
+			if err := e.commit(ctx, ch, last); err != nil {
+				return stats, err
+			}
+			pending = 0
+		}
+	}
+
+	// 服务端缺失的尾部 seq 也视为已经导出，避免下次重复扫描
+	if err := e.commit(ctx, ch, stats.EndSeq); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// before 判断消息是否早于 Since
+func (e *Exporter) before(m wukong.Message) bool {
+	return !e.since.IsZero() && m.Timestamp < e.since.Unix()
+}
+
+// after 判断消息是否不早于 Until
+func (e *Exporter) after(m wukong.Message) bool {
+	return !e.until.IsZero() && m.Timestamp >= e.until.Unix()
+}
+
+// commit 刷新 Writer 并保存断点
+func (e *Exporter) commit(ctx context.Context, ch wukong.ChannelKey, seq int64) error {
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("export: channel %s: flush: %w", ch, err)
+	}
+	if e.checkpoint == nil {
+		return nil
+	}
+	if err := e.checkpoint.Save(ctx, ch, seq); err != nil {
+		return fmt.Errorf("export: channel %s: save checkpoint: %w", ch, err)
+	}
+	return nil
+}
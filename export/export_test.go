@@ -0,0 +1,193 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/export"
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+)
+
+func TestExportPersonChannel(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "u2", ChannelType: wukong.ChannelTypePerson, Payload: "aGk="}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	exp := export.New(cli.Message, export.NewJSONLWriter(&buf), export.WithLoginUID("u2"))
+	stats, err := exp.ExportChannel(ctx, wukong.ChannelKey{ChannelID: "u1", ChannelType: wukong.ChannelTypePerson})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.EndSeq != 3 || stats.Exported != 3 {
+		t.Errorf("stats = %+v, want EndSeq 3 and 3 exported", stats)
+	}
+
+	n := 0
+	for _, err := range export.ReadJSONL(&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("wrote %d records, want 3", n)
+	}
+}
+
+func TestExportStopsAtUntil(t *testing.T) {
+	clock := time.Unix(1_700_000_000, 0)
+	srv := wukongtest.NewServer(wukongtest.WithClock(func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}))
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	g1 := wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}
+	if _, err := cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1"}}); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		if _, err := cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	until := time.Unix(srv.Messages("g1", wukong.ChannelTypeGroup)[3].Timestamp, 0)
+
+	store := wukong.NewMemoryCheckpointStore()
+	var buf bytes.Buffer
+	exp := export.New(cli.Message, export.NewJSONLWriter(&buf), export.WithPageSize(2), export.WithUntil(until), export.WithCheckpoint(store))
+	srv.ResetRequests()
+	stats, err := exp.ExportChannel(ctx, g1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Exported != 3 || stats.EndSeq != 3 {
+		t.Errorf("stats = %+v, want 3 exported up to seq 3", stats)
+	}
+	// seq 1-2 与 seq 3-4 两页，第 4 条不早于 Until 后不再拉取
+	srv.AssertCallCount(t, "/channel/messagesync", 2)
+	if seq, _ := store.Load(ctx, g1); seq != 3 {
+		t.Errorf("checkpoint = %d, want 3", seq)
+	}
+
+	// 放宽 Until 后从断点继续
+	exp = export.New(cli.Message, export.NewJSONLWriter(&buf), export.WithCheckpoint(store))
+	stats, err = exp.ExportChannel(ctx, g1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.StartSeq != 4 || stats.Exported != 7 {
+		t.Errorf("resumed stats = %+v, want 7 exported from seq 4", stats)
+	}
+}
+
+func TestExportColumnar(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	g1 := wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}
+	if _, err := cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1"}}); err != nil {
+		t.Fatal(err)
+	}
+	text, err := payload.Encode(&payload.Text{Content: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{text, "aGk=", text, text, "aGk="} {
+		if _, err := cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var jsonl, columnar bytes.Buffer
+	if _, err := export.New(cli.Message, export.NewJSONLWriter(&jsonl), export.WithPageSize(2)).ExportChannel(ctx, g1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := export.New(cli.Message, export.NewColumnarWriter(&columnar), export.WithPageSize(2)).ExportChannel(ctx, g1); err != nil {
+		t.Fatal(err)
+	}
+
+	// 每页 Flush 一次：2、2、1 三个行组
+	if got := strings.Count(columnar.String(), "\n"); got != 3 {
+		t.Errorf("wrote %d row groups, want 3:\n%s", got, columnar.String())
+	}
+	if !strings.HasPrefix(columnar.String(), `{"rows":2,"message_id":[`) {
+		t.Errorf("row group is not column-oriented:\n%s", columnar.String())
+	}
+
+	var want, got []export.Record
+	for rec, err := range export.ReadJSONL(&jsonl) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, rec)
+	}
+	for rec, err := range export.ReadColumnar(&columnar) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, rec)
+	}
+	if len(want) != 5 || !reflect.DeepEqual(got, want) {
+		t.Errorf("columnar records = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestColumnarWriterRowGroupSize(t *testing.T) {
+	var buf bytes.Buffer
+	w := export.NewColumnarWriter(&buf, export.WithRowGroupSize(2))
+	for seq := int64(1); seq <= 5; seq++ {
+		if err := w.Write(export.Record{Message: wukong.Message{MessageSeq: seq, ChannelID: "g1"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("wrote %d row groups, want 3:\n%s", got, buf.String())
+	}
+	var seqs []int64
+	for rec, err := range export.ReadColumnar(&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		seqs = append(seqs, rec.MessageSeq)
+	}
+	if !reflect.DeepEqual(seqs, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("seqs = %v, want 1-5", seqs)
+	}
+}
+
+func TestReadColumnarRejectsMismatchedColumns(t *testing.T) {
+	r := strings.NewReader(`{"rows":2,"message_seq":[1]}` + "\n")
+	for _, err := range export.ReadColumnar(r) {
+		if err == nil {
+			t.Fatal("expected an error for mismatched columns")
+		}
+		return
+	}
+	t.Fatal("ReadColumnar yielded nothing")
+}
@@ -0,0 +1,328 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+)
+
+// Record 导出的一条消息，在原始消息的基础上附带解码后的内容
+type Record struct {
+	wukong.Message
+	// ContentType 内容类型，Payload 不是 WuKong 标准内容 JSON 时为 0
+	ContentType payload.ContentType `json:"content_type,omitempty"`
+	// Content 解码后的内容 JSON，Payload 无法解码时为空，此时以原始 Payload 为准
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+// NewRecord 解码消息的 Payload 并生成 Record，Content 保留原始的内容 JSON
+func NewRecord(m wukong.Message) Record {
+	rec := Record{Message: m}
+	c, err := payload.Decode(m.Payload)
+	if err != nil {
+		return rec
+	}
+	rec.ContentType = c.ContentType()
+
+	raw, err := base64.StdEncoding.DecodeString(m.Payload)
+	if err != nil {
+		// 兼容未经 base64 编码的 JSON
+		raw = []byte(m.Payload)
+	}
+	var buf bytes.Buffer
+	if json.Compact(&buf, raw) == nil {
+		rec.Content = buf.Bytes()
+	}
+	return rec
+}
+
+// Writer 导出消息的目标格式
+// Flush 之后写入的数据需要已经交给底层 io.Writer，Exporter 在保存断点前调用 Flush
+type Writer interface {
+	Write(rec Record) error
+	Flush() error
+}
+
+// JSONLWriter 每行写入一条 JSON 格式的 Record
+type JSONLWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+var _ Writer = (*JSONLWriter)(nil)
+
+// NewJSONLWriter 创建 JSONL 写入器
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{w: bw, enc: enc}
+}
+
+// Write 实现 Writer
+func (w *JSONLWriter) Write(rec Record) error {
+	return w.enc.Encode(rec)
+}
+
+// Flush 实现 Writer
+func (w *JSONLWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// csvHeader CSV 的列，content 列为解码后的内容 JSON，time 列为 RFC 3339 格式的 UTC 时间
+var csvHeader = []string{
+	"message_id", "message_seq", "client_msg_no", "from_uid", "channel_id", "channel_type",
+	"timestamp", "time", "content_type", "content", "payload",
+}
+
+// CSVOption 配置 CSVWriter
+type CSVOption func(*CSVWriter)
+
+// WithoutCSVHeader 不写入表头，用于从断点继续导出并追加到已有文件
+func WithoutCSVHeader() CSVOption {
+	return func(w *CSVWriter) {
+		w.headerDone = true
+	}
+}
+
+// CSVWriter 以 CSV 格式写入 Record，第一次写入时输出表头
+type CSVWriter struct {
+	w          *csv.Writer
+	headerDone bool
+}
+
+var _ Writer = (*CSVWriter)(nil)
+
+// NewCSVWriter 创建 CSV 写入器
+func NewCSVWriter(w io.Writer, opts ...CSVOption) *CSVWriter {
+	cw := &CSVWriter{w: csv.NewWriter(w)}
+	for _, opt := range opts {
+		opt(cw)
+	}
+	return cw
+}
+
+// Write 实现 Writer
+func (w *CSVWriter) Write(rec Record) error {
+	if !w.headerDone {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerDone = true
+	}
+
+	contentType := ""
+	if rec.ContentType != 0 {
+		contentType = strconv.Itoa(int(rec.ContentType))
+	}
+	return w.w.Write([]string{
+		strconv.FormatInt(rec.MessageID, 10),
+		strconv.FormatInt(rec.MessageSeq, 10),
+		rec.ClientMsgNo,
+		rec.FromUID,
+		rec.ChannelID,
+		strconv.Itoa(int(rec.ChannelType)),
+		strconv.FormatInt(rec.Timestamp, 10),
+		time.Unix(rec.Timestamp, 0).UTC().Format(time.RFC3339),
+		contentType,
+		string(rec.Content),
+		rec.Payload,
+	})
+}
+
+// Flush 实现 Writer
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// defaultRowGroupSize ColumnarWriter 默认的行组大小
+const defaultRowGroupSize = 1000
+
+// ColumnarOption 配置 ColumnarWriter
+type ColumnarOption func(*ColumnarWriter)
+
+// WithRowGroupSize 每个行组最多包含的记录数，默认 1000，<= 0 时使用默认值
+func WithRowGroupSize(n int) ColumnarOption {
+	return func(w *ColumnarWriter) {
+		w.rowGroupSize = n
+	}
+}
+
+// rowGroup 列式文件中的一个行组，同一列的值连续存放，列与 CSV 相同（不含 time 列）
+type rowGroup struct {
+	Rows        int                   `json:"rows"`
+	MessageID   []int64               `json:"message_id"`
+	MessageSeq  []int64               `json:"message_seq"`
+	ClientMsgNo []string              `json:"client_msg_no"`
+	FromUID     []string              `json:"from_uid"`
+	ChannelID   []string              `json:"channel_id"`
+	ChannelType []wukong.ChannelType  `json:"channel_type"`
+	Timestamp   []int64               `json:"timestamp"`
+	ContentType []payload.ContentType `json:"content_type"`
+	Content     []json.RawMessage     `json:"content"`
+	Payload     []string              `json:"payload"`
+}
+
+func (g *rowGroup) append(rec Record) {
+	g.Rows++
+	g.MessageID = append(g.MessageID, rec.MessageID)
+	g.MessageSeq = append(g.MessageSeq, rec.MessageSeq)
+	g.ClientMsgNo = append(g.ClientMsgNo, rec.ClientMsgNo)
+	g.FromUID = append(g.FromUID, rec.FromUID)
+	g.ChannelID = append(g.ChannelID, rec.ChannelID)
+	g.ChannelType = append(g.ChannelType, rec.ChannelType)
+	g.Timestamp = append(g.Timestamp, rec.Timestamp)
+	g.ContentType = append(g.ContentType, rec.ContentType)
+	g.Content = append(g.Content, rec.Content)
+	g.Payload = append(g.Payload, rec.Payload)
+}
+
+// valid 检查每一列的长度是否与行数一致
+func (g *rowGroup) valid() bool {
+	for _, n := range []int{
+		len(g.MessageID), len(g.MessageSeq), len(g.ClientMsgNo), len(g.FromUID), len(g.ChannelID),
+		len(g.ChannelType), len(g.Timestamp), len(g.ContentType), len(g.Content), len(g.Payload),
+	} {
+		if n != g.Rows {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *rowGroup) record(i int) Record {
+	rec := Record{
+		Message: wukong.Message{
+			MessageID:   g.MessageID[i],
+			MessageSeq:  g.MessageSeq[i],
+			ClientMsgNo: g.ClientMsgNo[i],
+			FromUID:     g.FromUID[i],
+			ChannelID:   g.ChannelID[i],
+			ChannelType: g.ChannelType[i],
+			Timestamp:   g.Timestamp[i],
+			Payload:     g.Payload[i],
+		},
+		ContentType: g.ContentType[i],
+	}
+	if c := g.Content[i]; len(c) > 0 && string(c) != "null" {
+		rec.Content = c
+	}
+	return rec
+}
+
+// ColumnarWriter 以类似 Parquet 的列式格式写入 Record
+// 记录先在内存中缓冲为行组，行组写满或 Flush 时按列输出为一行 JSON，
+// 内存中最多保留一个行组；每个行组相互独立，因此从断点继续时可以直接追加到已有文件
+// 文件通过 ReadColumnar 读取
+type ColumnarWriter struct {
+	w            *bufio.Writer
+	enc          *json.Encoder
+	rowGroupSize int
+	group        rowGroup
+}
+
+var _ Writer = (*ColumnarWriter)(nil)
+
+// NewColumnarWriter 创建列式写入器
+// Exporter 每导出一页调用一次 Flush，因此行组大小同时受 WithPageSize 限制
+func NewColumnarWriter(w io.Writer, opts ...ColumnarOption) *ColumnarWriter {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	cw := &ColumnarWriter{w: bw, enc: enc}
+	for _, opt := range opts {
+		opt(cw)
+	}
+	if cw.rowGroupSize <= 0 {
+		cw.rowGroupSize = defaultRowGroupSize
+	}
+	return cw
+}
+
+// Write 实现 Writer
+func (w *ColumnarWriter) Write(rec Record) error {
+	w.group.append(rec)
+	if w.group.Rows >= w.rowGroupSize {
+		return w.writeGroup()
+	}
+	return nil
+}
+
+// Flush 实现 Writer，把未写满的行组一并写出
+func (w *ColumnarWriter) Flush() error {
+	if err := w.writeGroup(); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *ColumnarWriter) writeGroup() error {
+	if w.group.Rows == 0 {
+		return nil
+	}
+	if err := w.enc.Encode(&w.group); err != nil {
+		return err
+	}
+	w.group = rowGroup{}
+	return nil
+}
+
+// ReadColumnar 逐条读取 ColumnarWriter 写出的 Record，内存中最多保留一个行组
+// 解码失败或行组中各列长度不一致时产出一次错误后结束
+func ReadColumnar(r io.Reader) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		dec := json.NewDecoder(bufio.NewReader(r))
+		for n := 0; ; n++ {
+			var g rowGroup
+			err := dec.Decode(&g)
+			if err == io.EOF {
+				return
+			}
+			if err == nil && !g.valid() {
+				err = fmt.Errorf("export: row group %d: column lengths do not match %d rows", n, g.Rows)
+			}
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			for i := 0; i < g.Rows; i++ {
+				if !yield(g.record(i), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ReadJSONL 逐条读取 JSONLWriter 写出的 Record，不会把整个文件读入内存
+// 解码失败时产出一次错误后结束
+func ReadJSONL(r io.Reader) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		dec := json.NewDecoder(bufio.NewReader(r))
+		for {
+			var rec Record
+			err := dec.Decode(&rec)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			if !yield(rec, nil) {
+				return
+			}
+		}
+	}
+}
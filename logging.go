@@ -0,0 +1,295 @@
+package wukong_go_sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// redacted 替换敏感字段的占位符
+const redacted = "[REDACTED]"
+
+// LogOptions 控制 Config.Logger 的输出内容
+type LogOptions struct {
+	// Level 调用成功时的日志级别，默认 slog.LevelDebug
+	Level slog.Leveler
+	// ErrorLevel 调用失败时的日志级别，默认 slog.LevelError
+	ErrorLevel slog.Leveler
+	// RetryLevel 每次重试时的日志级别，默认 slog.LevelWarn
+	RetryLevel slog.Leveler
+
+	// LogBodies 是否在 Debug 级别输出请求体和响应体
+	LogBodies bool
+	// MaxBodyBytes 输出请求体/响应体的最大字节数，超出部分被截断，默认 1024
+	MaxBodyBytes int
+	// RedactPayload 是否隐藏消息的 Payload 字段
+	RedactPayload bool
+}
+
+func (o LogOptions) level() slog.Leveler {
+	if o.Level == nil {
+		return slog.LevelDebug
+	}
+	return o.Level
+}
+
+func (o LogOptions) errorLevel() slog.Leveler {
+	if o.ErrorLevel == nil {
+		return slog.LevelError
+	}
+	return o.ErrorLevel
+}
+
+func (o LogOptions) retryLevel() slog.Leveler {
+	if o.RetryLevel == nil {
+		return slog.LevelWarn
+	}
+	return o.RetryLevel
+}
+
+func (o LogOptions) maxBodyBytes() int {
+	if o.MaxBodyBytes <= 0 {
+		return 1024
+	}
+	return o.MaxBodyBytes
+}
+
+// loggingMiddleware 为每次调用输出一条结构化日志
+func loggingMiddleware(logger *slog.Logger, opts LogOptions) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, op *Operation) error {
+			start := time.Now()
+			err := next(ctx, op)
+
+			attrs := []slog.Attr{
+				slog.String("op", op.Name),
+				slog.String("method", op.Method),
+				slog.String("path", op.Path),
+				slog.Int("status", op.StatusCode),
+				slog.Int("attempts", op.Attempts),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if len(op.Header) > 0 {
+				attrs = append(attrs, slog.Any("header", redactHeader(op.Header)))
+			}
+
+			if opts.LogBodies && logger.Enabled(ctx, slog.LevelDebug) {
+				attrs = append(attrs, slog.String("request", truncateBody(marshalForLog(redactRequest(op.Request, opts.RedactPayload)), opts.maxBodyBytes())))
+
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					attrs = append(attrs, slog.String("response", truncateBody(redactJSON(apiErr.Body, opts.RedactPayload), opts.maxBodyBytes())))
+				} else if err == nil {
+					attrs = append(attrs, slog.String("response", truncateBody(marshalForLog(redactResponse(op.Response, opts.RedactPayload)), opts.maxBodyBytes())))
+				}
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, opts.errorLevel().Level(), "wukongim call failed", attrs...)
+			} else {
+				logger.LogAttrs(ctx, opts.level().Level(), "wukongim call", attrs...)
+			}
+			return err
+		}
+	}
+}
+
+// logRetry 输出一次即将重试的尝试
+func logRetry(ctx context.Context, logger *slog.Logger, opts LogOptions, attempt RetryAttempt) {
+	attrs := []slog.Attr{
+		slog.String("op", attempt.Name),
+		slog.String("method", attempt.Method),
+		slog.String("path", attempt.Path),
+		slog.Int("status", attempt.StatusCode),
+		slog.Int("attempt", attempt.Attempt),
+		slog.Duration("backoff", attempt.Backoff),
+	}
+	if attempt.Err != nil {
+		attrs = append(attrs, slog.String("error", attempt.Err.Error()))
+	}
+	logger.LogAttrs(ctx, opts.retryLevel().Level(), "wukongim call retrying", attrs...)
+}
+
+// redactHeader 返回隐藏了认证信息的请求头副本
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, key := range []string{"Authorization", "Token"} {
+		if out.Get(key) != "" {
+			out.Set(key, redacted)
+		}
+	}
+	return out
+}
+
+// redactDebugLog 返回 resty 调试输出的脱敏回调，隐藏认证信息以及请求体、响应体中的 token、password 等字段
+// 开启 LogOptions.RedactPayload 时同样隐藏消息的 payload 字段
+func redactDebugLog(opts LogOptions) func(*resty.DebugLog) {
+	return func(dl *resty.DebugLog) {
+		if dl.Request != nil {
+			dl.Request.Header = redactHeader(dl.Request.Header)
+			dl.Request.Body = string(redactJSON([]byte(dl.Request.Body), opts.RedactPayload))
+		}
+		if dl.Response != nil {
+			dl.Response.Body = string(redactJSON([]byte(dl.Response.Body), opts.RedactPayload))
+		}
+	}
+}
+
+// sensitiveFields 需要在日志中隐藏的 JSON 字段
+var sensitiveFields = map[string]bool{
+	"token":    true,
+	"password": true,
+}
+
+// redactJSON 隐藏 JSON 中任意层级的敏感字段，body 不是 JSON 时原样返回
+// redactPayload 为 true 时 payload 字段也会被隐藏
+func redactJSON(body []byte, redactPayload bool) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	// 保留 message_id 等 int64 的精度
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !redactValue(v, redactPayload) {
+		return body
+	}
+
+	var out []byte
+	var err error
+	// resty 输出的 JSON 以三个空格缩进
+	if bytes.ContainsRune(trimmed, '\n') {
+		out, err = json.MarshalIndent(v, "", "   ")
+	} else {
+		out, err = json.Marshal(v)
+	}
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// redactValue 原地替换敏感字段，返回是否有字段被替换
+func redactValue(v any, redactPayload bool) bool {
+	changed := false
+	switch x := v.(type) {
+	case map[string]any:
+		for k, fv := range x {
+			key := strings.ToLower(k)
+			if sensitiveFields[key] || (redactPayload && key == "payload") {
+				if s, ok := fv.(string); !ok || s != "" {
+					x[k] = redacted
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(fv, redactPayload) || changed
+		}
+	case []any:
+		for _, ev := range x {
+			changed = redactValue(ev, redactPayload) || changed
+		}
+	}
+	return changed
+}
+
+// redactRequest 返回隐藏了敏感字段的请求体副本，不会修改原请求
+func redactRequest(req any, redactPayload bool) any {
+	switch r := req.(type) {
+	case *UpdateUserTokenRequest:
+		if r == nil {
+			return r
+		}
+		cp := *r
+		cp.Token = redacted
+		return &cp
+	case *ManagerLoginRequest:
+		if r == nil {
+			return r
+		}
+		cp := *r
+		cp.Password = redacted
+		return &cp
+	case *SendMessageRequest:
+		if r == nil || !redactPayload {
+			return r
+		}
+		cp := *r
+		cp.Payload = redacted
+		return &cp
+	case []SendMessageRequest:
+		if !redactPayload {
+			return r
+		}
+		cp := make([]SendMessageRequest, len(r))
+		copy(cp, r)
+		for i := range cp {
+			cp[i].Payload = redacted
+		}
+		return cp
+	}
+	return req
+}
+
+// redactResponse 返回隐藏了敏感字段的响应体副本，不会修改原响应
+func redactResponse(resp any, redactPayload bool) any {
+	switch r := resp.(type) {
+	case *ManagerLoginResponse:
+		if r == nil {
+			return r
+		}
+		cp := *r
+		cp.Token = redacted
+		return &cp
+	case *Message:
+		if r == nil || !redactPayload {
+			return r
+		}
+		cp := *r
+		cp.Payload = redacted
+		return &cp
+	case *[]Message:
+		if r == nil || !redactPayload {
+			return r
+		}
+		cp := make([]Message, len(*r))
+		copy(cp, *r)
+		for i := range cp {
+			cp[i].Payload = redacted
+		}
+		return cp
+	}
+	return resp
+}
+
+func marshalForLog(v any) []byte {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// truncateBody 把 body 截断到 limit 字节以内
+func truncateBody(body []byte, limit int) string {
+	if len(body) <= limit {
+		return string(body)
+	}
+	return strings.ToValidUTF8(string(body[:limit]), "") + "...(truncated)"
+}
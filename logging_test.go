@@ -0,0 +1,155 @@
+package wukong_go_sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		in            string
+		redactPayload bool
+		want          string
+	}{
+		{"top level", `{"uid":"u1","token":"SECRET"}`, false, `{"token":"[REDACTED]","uid":"u1"}`},
+		{"nested", `{"user":{"password":"SECRET"},"list":[{"Token":"SECRET"}]}`, false, `{"list":[{"Token":"[REDACTED]"}],"user":{"password":"[REDACTED]"}}`},
+		{"keeps int64", `{"token":"SECRET","message_id":1234567890123456789}`, false, `{"message_id":1234567890123456789,"token":"[REDACTED]"}`},
+		{"nothing to redact", `{"uid": "u1"}`, false, `{"uid": "u1"}`},
+		{"empty token", `{"token":""}`, false, `{"token":""}`},
+		{"not json", `token=SECRET`, false, `token=SECRET`},
+		{"invalid json", `{"token":"SECRET"`, false, `{"token":"SECRET"`},
+		{"payload kept", `{"payload":"aGk="}`, false, `{"payload":"aGk="}`},
+		{"payload", `{"payload":"aGk=","messages":[{"Payload":{"type":1}}],"token":"SECRET"}`, true, `{"messages":[{"Payload":"[REDACTED]"}],"payload":"[REDACTED]","token":"[REDACTED]"}`},
+		{"empty payload", `{"payload":""}`, true, `{"payload":""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactJSON([]byte(tt.in), tt.redactPayload)); got != tt.want {
+				t.Errorf("redactJSON(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// captureStderr 捕获 fn 执行期间写入 os.Stderr 的内容，resty 的调试输出默认写入 os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	fn()
+	_ = w.Close()
+	return <-done
+}
+
+func TestDebugOutputRedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/manager/login" {
+			_, _ = io.WriteString(w, `{"token":"SECRET-LOGIN-TOKEN","expire":3600}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	out := captureStderr(t, func() {
+		cli := NewClient(Config{BaseURL: srv.URL, Token: "SECRET-API-TOKEN", Debug: true})
+		ctx := context.Background()
+		if _, err := cli.User.UpdateToken(ctx, &UpdateUserTokenRequest{UID: "u1", Token: "SECRET-USER-TOKEN", DeviceFlag: 1}); err != nil {
+			t.Errorf("UpdateToken: %v", err)
+		}
+		if _, err := cli.Manager.Login(ctx, &ManagerLoginRequest{Username: "admin", Password: "SECRET-PASSWORD"}); err != nil {
+			t.Errorf("Login: %v", err)
+		}
+	})
+
+	if !strings.Contains(out, "/user/token") || !strings.Contains(out, redacted) {
+		t.Fatalf("debug output does not look like a resty dump:\n%s", out)
+	}
+	for _, secret := range []string{"SECRET-API-TOKEN", "SECRET-USER-TOKEN", "SECRET-PASSWORD", "SECRET-LOGIN-TOKEN"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("debug output leaks %s:\n%s", secret, out)
+		}
+	}
+}
+
+func TestDebugOutputRedactsPayload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/channel/messagesync" {
+			_, _ = io.WriteString(w, `[{"message_seq":1,"payload":"U0VDUkVULVJFQ0VJVkVE"}]`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"message_id":1,"message_seq":1}`)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		redactPayload bool
+	}{
+		{"kept", false},
+		{"redacted", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureStderr(t, func() {
+				cli := NewClient(Config{BaseURL: srv.URL, Debug: true, LogOptions: LogOptions{RedactPayload: tt.redactPayload}})
+				ctx := context.Background()
+				if _, err := cli.Message.SendMessage(ctx, &SendMessageRequest{ChannelID: "u2", ChannelType: 1, Payload: "U0VDUkVULVNFTlQ="}); err != nil {
+					t.Errorf("SendMessage: %v", err)
+				}
+				if _, err := cli.Message.MessageSync(ctx, &MessageSyncRequest{ChannelID: "g1", ChannelType: 2}); err != nil {
+					t.Errorf("MessageSync: %v", err)
+				}
+			})
+
+			if !strings.Contains(out, "/message/send") {
+				t.Fatalf("debug output does not look like a resty dump:\n%s", out)
+			}
+			for _, p := range []string{"U0VDUkVULVNFTlQ=", "U0VDUkVULVJFQ0VJVkVE"} {
+				if strings.Contains(out, p) == tt.redactPayload {
+					t.Errorf("payload %s present = %v, want %v:\n%s", p, !tt.redactPayload, !tt.redactPayload, out)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerRedactsErrorBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"status":400,"msg":"bad token","token":"SECRET-USER-TOKEN"}`)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cli := NewClient(Config{BaseURL: srv.URL, Debug: true, Logger: logger})
+	if _, err := cli.User.UpdateToken(context.Background(), &UpdateUserTokenRequest{UID: "u1", Token: "SECRET-USER-TOKEN"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(buf.String(), "SECRET") {
+		t.Errorf("log leaks a secret:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,207 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoBatchResponse 批量发送成功，但服务端响应中没有与该消息对应的结果
+var ErrNoBatchResponse = errors.New("wukongim: no response for message in batch")
+
+// ErrDuplicateClientMsgNo SendMany 中有多条消息使用同一个 ClientMsgNo，只发送第一条
+var ErrDuplicateClientMsgNo = errors.New("wukongim: duplicate client_msg_no in batch")
+
+// SendManyRequest 分批发送大量消息的请求
+type SendManyRequest struct {
+	Messages []SendMessageRequest
+	// ChunkSize 每次 BatchSendMessage 发送的消息数，默认 100
+	ChunkSize int
+	// Concurrency 最大并发请求数，默认 4
+	Concurrency int
+}
+
+// BatchResultItem 单条消息的发送结果
+type BatchResultItem struct {
+	// Index 消息在 SendManyRequest.Messages 中的下标
+	Index int
+	// Request 实际发送的请求，未指定 ClientMsgNo 时已经填入自动生成的值
+	Request     SendMessageRequest
+	MessageID   int64
+	MessageSeq  int64
+	ClientMsgNo string
+	// Err 发送失败的原因，批次请求失败时同一批次的消息共享同一个错误
+	Err error
+}
+
+// BatchResult SendMany 的结果，Items 与输入的消息一一对应且顺序一致
+type BatchResult struct {
+	Items []BatchResultItem
+}
+
+// Succeeded 返回发送成功的消息
+func (r *BatchResult) Succeeded() []BatchResultItem {
+	var out []BatchResultItem
+	for _, it := range r.Items {
+		if it.Err == nil {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// Failed 返回发送失败的消息
+func (r *BatchResult) Failed() []BatchResultItem {
+	var out []BatchResultItem
+	for _, it := range r.Items {
+		if it.Err != nil {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// FailedRequests 返回发送失败的请求，ClientMsgNo 与第一次发送时相同，可以直接再次交给 SendMany 重试
+func (r *BatchResult) FailedRequests() []SendMessageRequest {
+	var out []SendMessageRequest
+	for _, it := range r.Items {
+		if it.Err != nil {
+			out = append(out, it.Request)
+		}
+	}
+	return out
+}
+
+// SendMany 把大量消息按 ChunkSize 分批，通过 BatchSendMessage 并发发送，并发数受 Concurrency 限制
+// 响应按 ClientMsgNo 对应回输入的消息，未指定 ClientMsgNo 的消息会自动生成，因此开启重试时批次可以安全重试
+// ClientMsgNo 与前面的消息重复时不会发送，结果中记录 ErrDuplicateClientMsgNo
+// 部分批次失败时同时返回结果与由 errors.Join 合并的错误（每个失败批次一个），通过 BatchResult.Failed 获取失败的消息
+func (s *MessageService) SendMany(ctx context.Context, req *SendManyRequest, opts ...CallOption) (*BatchResult, error) {
+	return SendMany(ctx, s, req, opts...)
+}
+
+// SendMany 通过 api.BatchSendMessage 分批发送大量消息，行为与 MessageService.SendMany 相同
+func SendMany(ctx context.Context, api MessageAPI, req *SendManyRequest, opts ...CallOption) (*BatchResult, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	chunkSize := req.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 100
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+	)
+
+	// 同一批次中重复的 ClientMsgNo 无法把响应对应回消息，服务端也只会保存一条，因此只发送第一条
+	result := &BatchResult{Items: make([]BatchResultItem, len(req.Messages))}
+	pending := make([]*BatchResultItem, 0, len(req.Messages))
+	first := make(map[string]int, len(req.Messages))
+	for i, msg := range req.Messages {
+		if msg.ClientMsgNo == "" {
+			msg.ClientMsgNo = NewClientMsgNo()
+		}
+		result.Items[i] = BatchResultItem{Index: i, Request: msg, ClientMsgNo: msg.ClientMsgNo}
+		if j, ok := first[msg.ClientMsgNo]; ok {
+			err := fmt.Errorf("message %d: %w: %s is also used by message %d", i, ErrDuplicateClientMsgNo, msg.ClientMsgNo, j)
+			result.Items[i].Err = err
+			errs = append(errs, err)
+			continue
+		}
+		first[msg.ClientMsgNo] = i
+		pending = append(pending, &result.Items[i])
+	}
+
+	for start := 0; start < len(pending); start += chunkSize {
+		items := pending[start:min(start+chunkSize, len(pending))]
+		lo, hi := items[0].Index, items[len(items)-1].Index
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			err := fmt.Errorf("messages %d-%d: %w", lo, hi, ctx.Err())
+			failChunk(items, err)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(lo, hi int, items []*BatchResultItem) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// 每个批次只修改自己的 items，不需要加锁
+			err := sendChunk(ctx, api, items, opts...)
+			if err == nil {
+				return
+			}
+			err = fmt.Errorf("messages %d-%d: %w", lo, hi, err)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}(lo, hi, items)
+	}
+	wg.Wait()
+
+	return result, errors.Join(errs...)
+}
+
+// sendChunk 发送一个批次，并把响应按 ClientMsgNo 写回 items，失败的消息同时记录在 items 中
+func sendChunk(ctx context.Context, api MessageAPI, items []*BatchResultItem, opts ...CallOption) error {
+	msgs := make([]SendMessageRequest, len(items))
+	for i := range items {
+		msgs[i] = items[i].Request
+	}
+
+	resp, err := api.BatchSendMessage(ctx, &BatchSendMessageRequest{Messages: msgs}, opts...)
+	if err != nil {
+		failChunk(items, err)
+		return err
+	}
+
+	byNo := make(map[string]BatchSendMessageResponseItem, len(resp))
+	for _, r := range resp {
+		if r.ClientMsgNo != "" {
+			byNo[r.ClientMsgNo] = r
+		}
+	}
+	missing := 0
+	for i := range items {
+		r, ok := byNo[items[i].ClientMsgNo]
+		// 服务端没有回传 client_msg_no 时按位置对应
+		if !ok && len(resp) == len(items) && resp[i].ClientMsgNo == "" {
+			r, ok = resp[i], true
+		}
+		if !ok {
+			items[i].Err = fmt.Errorf("%w: client_msg_no %s", ErrNoBatchResponse, items[i].ClientMsgNo)
+			missing++
+			continue
+		}
+		items[i].MessageID = r.MessageID
+		items[i].MessageSeq = r.MessageSeq
+	}
+	if missing > 0 {
+		return fmt.Errorf("%d of %d messages: %w", missing, len(items), ErrNoBatchResponse)
+	}
+	return nil
+}
+
+func failChunk(items []*BatchResultItem, err error) {
+	for i := range items {
+		items[i].Err = err
+	}
+}
@@ -0,0 +1,165 @@
+package wukong_go_sdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongmock"
+)
+
+func groupMessages(nos ...string) []wukong.SendMessageRequest {
+	out := make([]wukong.SendMessageRequest, len(nos))
+	for i, no := range nos {
+		out[i] = wukong.SendMessageRequest{ClientMsgNo: no, FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="}
+	}
+	return out
+}
+
+// batchServer 按收到的顺序为每条消息分配 seq，respond 可以改写响应
+type batchServer struct {
+	mu      sync.Mutex
+	seq     int64
+	batches [][]string
+	respond func(batch int, items []wukong.BatchSendMessageResponseItem) ([]wukong.BatchSendMessageResponseItem, error)
+}
+
+func (b *batchServer) api() *wukongmock.MessageAPI {
+	api := wukongmock.NewAPI().Message
+	api.BatchSendMessageFunc = func(_ context.Context, req *wukong.BatchSendMessageRequest, _ ...wukong.CallOption) ([]wukong.BatchSendMessageResponseItem, error) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		var nos []string
+		var items []wukong.BatchSendMessageResponseItem
+		for _, m := range req.Messages {
+			b.seq++
+			nos = append(nos, m.ClientMsgNo)
+			items = append(items, wukong.BatchSendMessageResponseItem{MessageID: 1000 + b.seq, MessageSeq: b.seq, ClientMsgNo: m.ClientMsgNo})
+		}
+		b.batches = append(b.batches, nos)
+		if b.respond != nil {
+			return b.respond(len(b.batches)-1, items)
+		}
+		return items, nil
+	}
+	return api
+}
+
+func TestSendManyChunksAndMatchesByClientMsgNo(t *testing.T) {
+	srv := &batchServer{respond: func(_ int, items []wukong.BatchSendMessageResponseItem) ([]wukong.BatchSendMessageResponseItem, error) {
+		// 响应顺序与请求不同，仍然按 ClientMsgNo 对应
+		slices.Reverse(items)
+		return items, nil
+	}}
+	msgs := groupMessages("a", "b", "c", "d", "")
+
+	res, err := wukong.SendMany(context.Background(), srv.api(), &wukong.SendManyRequest{Messages: msgs, ChunkSize: 2, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(srv.batches); got != 3 {
+		t.Fatalf("sent %d batches, want 3", got)
+	}
+	if len(srv.batches[0]) != 2 || len(srv.batches[1]) != 2 || len(srv.batches[2]) != 1 {
+		t.Errorf("batch sizes = %v, want 2, 2, 1", srv.batches)
+	}
+	for i, it := range res.Items {
+		if it.Index != i || it.Err != nil {
+			t.Errorf("item %d = %+v, want index %d without error", i, it, i)
+		}
+		if it.MessageSeq != int64(i+1) {
+			t.Errorf("item %d MessageSeq = %d, want %d", i, it.MessageSeq, i+1)
+		}
+	}
+	if no := res.Items[4].ClientMsgNo; len(no) != 32 || res.Items[4].Request.ClientMsgNo != no {
+		t.Errorf("generated ClientMsgNo = %q, want 32 hex chars also set on Request", no)
+	}
+}
+
+func TestSendManyPartialFailure(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	srv := &batchServer{respond: func(batch int, items []wukong.BatchSendMessageResponseItem) ([]wukong.BatchSendMessageResponseItem, error) {
+		switch batch {
+		case 1:
+			return nil, errUnavailable
+		case 2:
+			// 服务端漏掉了最后一条消息
+			return items[:1], nil
+		}
+		return items, nil
+	}}
+	msgs := groupMessages("a", "b", "c", "d", "e", "f")
+
+	res, err := wukong.SendMany(context.Background(), srv.api(), &wukong.SendManyRequest{Messages: msgs, ChunkSize: 2, Concurrency: 1})
+	if !errors.Is(err, errUnavailable) || !errors.Is(err, wukong.ErrNoBatchResponse) {
+		t.Fatalf("err = %v, want both the batch error and ErrNoBatchResponse", err)
+	}
+
+	var failed []string
+	for _, it := range res.Failed() {
+		failed = append(failed, it.ClientMsgNo)
+	}
+	if want := []string{"c", "d", "f"}; !slices.Equal(failed, want) {
+		t.Errorf("failed = %v, want %v", failed, want)
+	}
+	if !errors.Is(res.Items[2].Err, errUnavailable) || !errors.Is(res.Items[5].Err, wukong.ErrNoBatchResponse) {
+		t.Errorf("item errors = %v / %v", res.Items[2].Err, res.Items[5].Err)
+	}
+	if len(res.Succeeded()) != 3 {
+		t.Errorf("%d succeeded, want 3", len(res.Succeeded()))
+	}
+
+	retry := res.FailedRequests()
+	if len(retry) != 3 || retry[0].ClientMsgNo != "c" {
+		t.Errorf("FailedRequests = %v, want c, d, f with their ClientMsgNo", retry)
+	}
+}
+
+func TestSendManyMatchesByPositionWithoutClientMsgNo(t *testing.T) {
+	srv := &batchServer{respond: func(_ int, items []wukong.BatchSendMessageResponseItem) ([]wukong.BatchSendMessageResponseItem, error) {
+		for i := range items {
+			items[i].ClientMsgNo = ""
+		}
+		return items, nil
+	}}
+
+	res, err := wukong.SendMany(context.Background(), srv.api(), &wukong.SendManyRequest{Messages: groupMessages("a", "b", "c")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, it := range res.Items {
+		if it.MessageSeq != int64(i+1) {
+			t.Errorf("item %d MessageSeq = %d, want %d", i, it.MessageSeq, i+1)
+		}
+	}
+}
+
+func TestSendManyRejectsDuplicateClientMsgNo(t *testing.T) {
+	srv := &batchServer{}
+	msgs := groupMessages("a", "b", "a", "c", "b")
+
+	res, err := wukong.SendMany(context.Background(), srv.api(), &wukong.SendManyRequest{Messages: msgs, ChunkSize: 10})
+	if !errors.Is(err, wukong.ErrDuplicateClientMsgNo) {
+		t.Fatalf("err = %v, want ErrDuplicateClientMsgNo", err)
+	}
+	if want := [][]string{{"a", "b", "c"}}; fmt.Sprint(srv.batches) != fmt.Sprint(want) {
+		t.Errorf("sent %v, want %v", srv.batches, want)
+	}
+	for i, it := range res.Items {
+		dup := i == 2 || i == 4
+		if dup != errors.Is(it.Err, wukong.ErrDuplicateClientMsgNo) {
+			t.Errorf("item %d err = %v", i, it.Err)
+		}
+		if !dup && it.Err != nil {
+			t.Errorf("item %d err = %v, want nil", i, it.Err)
+		}
+	}
+	// 每条消息的 seq 只来自自己的响应
+	if res.Items[0].MessageSeq != 1 || res.Items[1].MessageSeq != 2 || res.Items[3].MessageSeq != 3 {
+		t.Errorf("seqs = %d %d %d, want 1 2 3", res.Items[0].MessageSeq, res.Items[1].MessageSeq, res.Items[3].MessageSeq)
+	}
+}
@@ -0,0 +1,265 @@
+package wukong_go_sdk
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+)
+
+// ErrInvalidMessage MessageBuilder 缺少必填字段
+var ErrInvalidMessage = errors.New("wukongim: invalid message")
+
+// MessageBuilder 以链式调用构造 SendMessageRequest，通过 NewMessage 创建
+//
+//	req, err := wukong.NewMessage("u1").
+//		To("g1", wukong.ChannelTypeGroup).
+//		Text("hello").
+//		Mention("u2", "u3").
+//		ExpireIn(24 * time.Hour).
+//		Build()
+//
+// 未指定 ClientMsgNo 时自动生成，因此构造出的请求在开启重试时可以安全重试
+type MessageBuilder struct {
+	from        string
+	channelID   string
+	channelType ChannelType
+	clientMsgNo string
+	tagKey      string
+
+	content payload.Content
+	raw     *string
+	mention *payload.Mention
+	reply   *payload.Reply
+
+	header MessageHeader
+	expire time.Duration
+}
+
+// NewMessage 创建消息构造器，from 为发送者 UID，只有内容为系统通知（payload.System）时可以为空
+func NewMessage(from string) *MessageBuilder {
+	return &MessageBuilder{
+		from:   from,
+		header: MessageHeader{RedDot: 1},
+	}
+}
+
+// To 设置接收频道，单聊时 channelID 为对方 UID
+func (b *MessageBuilder) To(channelID string, channelType ChannelType) *MessageBuilder {
+	b.channelID = channelID
+	b.channelType = channelType
+	return b
+}
+
+// Text 设置文本内容
+func (b *MessageBuilder) Text(text string) *MessageBuilder {
+	return b.Content(&payload.Text{Content: text})
+}
+
+// Content 设置类型化的消息内容
+func (b *MessageBuilder) Content(c payload.Content) *MessageBuilder {
+	b.content = c
+	b.raw = nil
+	return b
+}
+
+// Payload 直接使用原始字节作为 Payload（自动 base64 编码），会覆盖 Text / Content 设置的内容
+// 使用原始 Payload 时 Mention 与 ReplyTo 不生效
+func (b *MessageBuilder) Payload(raw []byte) *MessageBuilder {
+	p := base64.StdEncoding.EncodeToString(raw)
+	b.raw = &p
+	b.content = nil
+	return b
+}
+
+// Mention @ 指定用户，可以多次调用
+func (b *MessageBuilder) Mention(uids ...string) *MessageBuilder {
+	if b.mention == nil {
+		b.mention = &payload.Mention{}
+	}
+	b.mention.UIDs = append(b.mention.UIDs, uids...)
+	return b
+}
+
+// MentionAll @ 所有人
+func (b *MessageBuilder) MentionAll() *MessageBuilder {
+	if b.mention == nil {
+		b.mention = &payload.Mention{}
+	}
+	b.mention.All = 1
+	return b
+}
+
+// ReplyTo 回复一条消息，msg 为 nil 时忽略
+func (b *MessageBuilder) ReplyTo(msg *Message) *MessageBuilder {
+	if msg == nil {
+		b.reply = nil
+		return b
+	}
+
+	reply := &payload.Reply{
+		MessageID:  strconv.FormatInt(msg.MessageID, 10),
+		MessageSeq: msg.MessageSeq,
+		FromUID:    msg.FromUID,
+	}
+	var fields map[string]json.RawMessage
+	if raw, err := base64.StdEncoding.DecodeString(msg.Payload); err == nil && json.Unmarshal(raw, &fields) == nil {
+		// 被回复的消息本身也是回复时，沿用回复链的根消息，并去掉其中的 reply 避免层层嵌套
+		var orig payload.Reply
+		if r, ok := fields["reply"]; ok && json.Unmarshal(r, &orig) == nil {
+			reply.RootMID = orig.RootMID
+			if reply.RootMID == "" {
+				reply.RootMID = orig.MessageID
+			}
+			delete(fields, "reply")
+		}
+		reply.Payload, _ = json.Marshal(fields)
+	}
+	b.reply = reply
+	return b
+}
+
+// ClientMsgNo 指定客户端消息编号，默认自动生成
+func (b *MessageBuilder) ClientMsgNo(no string) *MessageBuilder {
+	b.clientMsgNo = no
+	return b
+}
+
+// TagKey 设置消息的 tag_key
+func (b *MessageBuilder) TagKey(key string) *MessageBuilder {
+	b.tagKey = key
+	return b
+}
+
+// NoPersist 消息不存储
+func (b *MessageBuilder) NoPersist() *MessageBuilder {
+	b.header.NoPersist = 1
+	return b
+}
+
+// NoRedDot 消息不显示红点（不计入未读数）
+func (b *MessageBuilder) NoRedDot() *MessageBuilder {
+	b.header.RedDot = 0
+	return b
+}
+
+// SyncOnce 消息只同步一次
+func (b *MessageBuilder) SyncOnce() *MessageBuilder {
+	b.header.SyncOnce = 1
+	return b
+}
+
+// ExpireIn 设置消息过期时间，不足一秒按一秒计算，<= 0 表示不过期
+func (b *MessageBuilder) ExpireIn(d time.Duration) *MessageBuilder {
+	b.expire = d
+	return b
+}
+
+// Build 校验并生成 SendMessageRequest，可以直接用于 SendMessage
+func (b *MessageBuilder) Build() (*SendMessageRequest, error) {
+	if b.channelID == "" || b.channelType == 0 {
+		return nil, fmt.Errorf("%w: channel is required, call To", ErrInvalidMessage)
+	}
+
+	var p string
+	switch {
+	case b.raw != nil:
+		p = *b.raw
+	case b.content != nil:
+		var err error
+		if p, err = b.encodeContent(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: content is required, call Text, Content or Payload", ErrInvalidMessage)
+	}
+
+	if b.from == "" && (b.content == nil || !b.content.ContentType().IsSystem()) {
+		return nil, fmt.Errorf("%w: sender is required unless the content is a system notification", ErrInvalidMessage)
+	}
+
+	clientMsgNo := b.clientMsgNo
+	if clientMsgNo == "" {
+		clientMsgNo = NewClientMsgNo()
+	}
+
+	header := b.header
+	return &SendMessageRequest{
+		Header:      &header,
+		ClientMsgNo: clientMsgNo,
+		FromUID:     b.from,
+		ChannelID:   b.channelID,
+		ChannelType: b.channelType,
+		Expire:      expireSeconds(b.expire),
+		Payload:     p,
+		TagKey:      b.tagKey,
+	}, nil
+}
+
+// BuildBatch 构造 BatchSendMessageRequest，任意一条消息校验失败时返回错误
+func BuildBatch(builders ...*MessageBuilder) (*BatchSendMessageRequest, error) {
+	req := &BatchSendMessageRequest{Messages: make([]SendMessageRequest, 0, len(builders))}
+	for i, b := range builders {
+		msg, err := b.Build()
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		req.Messages = append(req.Messages, *msg)
+	}
+	return req, nil
+}
+
+// encodeContent 编码内容，并把 mention、reply 合并到内容 JSON 中
+func (b *MessageBuilder) encodeContent() (string, error) {
+	data, err := payload.EncodeJSON(b.content)
+	if err != nil {
+		return "", err
+	}
+
+	if b.mention != nil || b.reply != nil {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return "", err
+		}
+		if b.mention != nil {
+			fields["mention"], _ = json.Marshal(b.mention)
+		}
+		if b.reply != nil {
+			fields["reply"], _ = json.Marshal(b.reply)
+		}
+		if data, err = json.Marshal(fields); err != nil {
+			return "", err
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// expireSeconds 把时长转换为服务端使用的过期秒数
+func expireSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// NewClientMsgNo 生成随机的客户端消息编号（32 位十六进制），MessageBuilder、SendMany 等未指定 ClientMsgNo 时使用
+func NewClientMsgNo() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// StableClientMsgNo 根据 parts 生成确定的客户端消息编号，格式与 NewClientMsgNo 相同
+// 同一组 parts 总是得到相同的编号，适合需要在重试或重复执行时去重的场景
+func StableClientMsgNo(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
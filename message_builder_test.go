@@ -0,0 +1,199 @@
+package wukong_go_sdk_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+)
+
+func TestMessageBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *wukong.MessageBuilder
+		wantErr bool
+	}{
+		{"missing channel", wukong.NewMessage("u1").Text("hi"), true},
+		{"missing channel type", wukong.NewMessage("u1").To("g1", 0).Text("hi"), true},
+		{"missing payload", wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup), true},
+		{"missing sender", wukong.NewMessage("").To("g1", wukong.ChannelTypeGroup).Text("hi"), true},
+		{"missing sender with raw payload", wukong.NewMessage("").To("g1", wukong.ChannelTypeGroup).Payload([]byte("hi")), true},
+		{"system notification without sender", wukong.NewMessage("").To("g1", wukong.ChannelTypeGroup).Content(&payload.System{Type: 1001, Content: "{0} joined"}), false},
+		{"text", wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Text("hi"), false},
+		{"raw payload", wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Payload([]byte("hi")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			if tt.wantErr {
+				if !errors.Is(err, wukong.ErrInvalidMessage) || req != nil {
+					t.Errorf("Build = %+v, %v, want ErrInvalidMessage", req, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+		})
+	}
+
+	_, err := wukong.BuildBatch(
+		wukong.NewMessage("u1").To("u2", wukong.ChannelTypePerson).Text("hi"),
+		wukong.NewMessage("u1").Text("no channel"),
+	)
+	if !errors.Is(err, wukong.ErrInvalidMessage) {
+		t.Errorf("BuildBatch err = %v, want ErrInvalidMessage", err)
+	}
+}
+
+func TestMessageBuilderFields(t *testing.T) {
+	req, err := wukong.NewMessage("u1").
+		To("g1", wukong.ChannelTypeGroup).
+		Text("hi").
+		Mention("u2").
+		Mention("u3").
+		NoPersist().
+		NoRedDot().
+		SyncOnce().
+		TagKey("tag").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.FromUID != "u1" || req.ChannelID != "g1" || req.ChannelType != wukong.ChannelTypeGroup || req.TagKey != "tag" {
+		t.Errorf("req = %+v", req)
+	}
+	if h := req.Header; h == nil || *h != (wukong.MessageHeader{NoPersist: 1, RedDot: 0, SyncOnce: 1}) {
+		t.Errorf("Header = %+v", h)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(req.ClientMsgNo) {
+		t.Errorf("ClientMsgNo = %q, want 32 hex chars", req.ClientMsgNo)
+	}
+
+	c, err := payload.Decode(req.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, ok := c.(*payload.Text)
+	if !ok || text.Content != "hi" || text.Mention == nil || len(text.Mention.UIDs) != 2 {
+		t.Errorf("payload = %#v, want text mentioning u2 and u3", c)
+	}
+
+	other, err := wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Text("hi").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ClientMsgNo == req.ClientMsgNo {
+		t.Error("generated ClientMsgNo is not unique")
+	}
+	if other.Header.RedDot != 1 {
+		t.Errorf("default RedDot = %d, want 1", other.Header.RedDot)
+	}
+
+	fixed, err := wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Text("hi").ClientMsgNo("m1").Build()
+	if err != nil || fixed.ClientMsgNo != "m1" {
+		t.Errorf("ClientMsgNo = %q (err %v), want m1", fixed.ClientMsgNo, err)
+	}
+}
+
+func TestMessageBuilderExpireIn(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want int64
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{24 * time.Hour, 86400},
+	}
+	for _, tt := range tests {
+		req, err := wukong.NewMessage("u1").To("u2", wukong.ChannelTypePerson).Text("hi").ExpireIn(tt.d).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.Expire != tt.want {
+			t.Errorf("ExpireIn(%v) expire = %d, want %d", tt.d, req.Expire, tt.want)
+		}
+	}
+}
+
+func TestMessageBuilderReplyTo(t *testing.T) {
+	original, err := payload.Encode(&payload.Text{Content: "question"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := &wukong.Message{MessageID: 100, MessageSeq: 5, FromUID: "u2", Payload: original}
+
+	req, err := wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Text("answer").ReplyTo(first).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := decodeReply(t, req.Payload)
+	if reply.MessageID != "100" || reply.MessageSeq != 5 || reply.FromUID != "u2" || reply.RootMID != "" {
+		t.Errorf("reply = %+v", reply)
+	}
+	if quoted, err := payload.DecodeJSON(reply.Payload); err != nil || quoted.(*payload.Text).Content != "question" {
+		t.Errorf("quoted payload = %s (err %v)", reply.Payload, err)
+	}
+
+	// 回复一条回复时沿用根消息，并去掉被回复消息中的 reply
+	second := &wukong.Message{MessageID: 101, MessageSeq: 6, FromUID: "u1", Payload: req.Payload}
+	req, err = wukong.NewMessage("u2").To("g1", wukong.ChannelTypeGroup).Text("thanks").ReplyTo(second).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply = decodeReply(t, req.Payload)
+	if reply.MessageID != "101" || reply.RootMID != "100" {
+		t.Errorf("reply = %+v, want message 101 rooted at 100", reply)
+	}
+	quoted, err := payload.DecodeJSON(reply.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := quoted.(*payload.Text); text.Content != "answer" || text.Reply != nil {
+		t.Errorf("quoted = %+v, want answer without nested reply", text)
+	}
+
+	// nil 清除之前设置的回复
+	req, err = wukong.NewMessage("u1").To("g1", wukong.ChannelTypeGroup).Text("hi").ReplyTo(first).ReplyTo(nil).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := payload.Decode(req.Payload); c.(*payload.Text).Reply != nil {
+		t.Error("ReplyTo(nil) kept the reply")
+	}
+}
+
+func decodeReply(t *testing.T, p string) *payload.Reply {
+	t.Helper()
+	c, err := payload.Decode(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, ok := c.(*payload.Text)
+	if !ok || text.Reply == nil {
+		t.Fatalf("payload = %#v, want text with reply", c)
+	}
+	return text.Reply
+}
+
+func TestStableClientMsgNo(t *testing.T) {
+	a := wukong.StableClientMsgNo("order", "42")
+	if a != wukong.StableClientMsgNo("order", "42") {
+		t.Error("StableClientMsgNo is not deterministic")
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(a) {
+		t.Errorf("StableClientMsgNo = %q, want 32 hex chars", a)
+	}
+	for _, parts := range [][]string{{"order", "43"}, {"order4", "2"}, {"order42"}} {
+		if wukong.StableClientMsgNo(parts...) == a {
+			t.Errorf("StableClientMsgNo(%q) collides with (order, 42)", parts)
+		}
+	}
+}
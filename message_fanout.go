@@ -0,0 +1,232 @@
+package wukong_go_sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+)
+
+// FanoutRecipient 扇出发送的一个接收者，消息发送到以 UID 为 ID 的个人频道
+type FanoutRecipient struct {
+	UID string
+	// Fields 渲染模板时使用的字段，例如 {{.Fields.name}}
+	Fields map[string]any
+}
+
+// FanoutTemplate 扇出发送的消息模板
+type FanoutTemplate struct {
+	// FromUID 发送者，通常为系统账号
+	FromUID string
+	// Text 文本消息模板，使用 text/template 语法，以 FanoutRecipient 为数据渲染，例如 "{{.Fields.name}}，你好"
+	Text string
+	// Render 自定义渲染，设置后忽略 Text，返回接收者的消息内容
+	Render func(r FanoutRecipient) (payload.Content, error)
+
+	// ID 本次扇出的唯一标识，设置后每个接收者的 ClientMsgNo 由 ID 与 UID 确定，
+	// 重复执行（续传或重试失败的接收者）时不会产生重复消息；为空时随机生成
+	ID string
+	// Header 消息 header，为 nil 时显示红点
+	Header *MessageHeader
+	// Expire 消息过期时间，<= 0 表示不过期
+	Expire time.Duration
+	TagKey string
+}
+
+// FanoutOptions 扇出发送选项
+type FanoutOptions struct {
+	// ChunkSize / Concurrency 传给 SendMany，默认 100 / 4
+	ChunkSize   int
+	Concurrency int
+	// Rate 每秒最多发送的消息数，<= 0 表示不限制
+	Rate float64
+	// Cursor 从第几个接收者开始发送，用于从上次的 FanoutReport.Cursor 续传
+	Cursor int
+	// OnProgress 每处理完一组接收者后回调
+	OnProgress func(p FanoutProgress)
+}
+
+// FanoutProgress 扇出发送进度
+type FanoutProgress struct {
+	// Cursor 下一个待处理的接收者下标，可以保存下来用于续传
+	Cursor int
+	Total  int
+	Sent   int
+	Failed int
+}
+
+// FanoutFailure 一个接收者的失败原因，包括渲染失败与发送失败
+type FanoutFailure struct {
+	// Index 接收者在 recipients 中的下标
+	Index     int
+	Recipient FanoutRecipient
+	Err       error
+}
+
+// FanoutReport 扇出发送的最终结果，Sent / Failed 只统计本次调用处理的接收者
+type FanoutReport struct {
+	FanoutProgress
+	Failures []FanoutFailure
+}
+
+// Fanout 把同一模板渲染后发送给大量接收者的个人频道
+// 接收者按顺序分组，每组渲染后通过 SendMany 发送；ctx 取消后尽快停止，
+// 返回的 FanoutReport.Cursor 指向第一个未处理的接收者，传入 FanoutOptions.Cursor 即可续传
+// 单个接收者失败不会中断发送，记录在 FanoutReport.Failures 中；返回的 error 只表示模板无效或发送被取消
+func (s *MessageService) Fanout(ctx context.Context, tmpl *FanoutTemplate, recipients []FanoutRecipient, fanout FanoutOptions, opts ...CallOption) (*FanoutReport, error) {
+	return Fanout(ctx, s, tmpl, recipients, fanout, opts...)
+}
+
+// Fanout 通过 api.BatchSendMessage 扇出发送，行为与 MessageService.Fanout 相同
+func Fanout(ctx context.Context, api MessageAPI, tmpl *FanoutTemplate, recipients []FanoutRecipient, fanout FanoutOptions, opts ...CallOption) (*FanoutReport, error) {
+	if tmpl == nil {
+		return nil, nil
+	}
+
+	render, err := tmpl.renderer()
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := fanout.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 100
+	}
+	concurrency := fanout.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	// 每组的大小刚好让 SendMany 的所有并发都有事可做；限速时每组不超过一秒的配额，避免集中发送
+	window := chunkSize * concurrency
+	if fanout.Rate > 0 {
+		window = min(window, max(1, int(fanout.Rate)))
+	}
+	bucket := newTokenBucket(RateLimit{Rate: fanout.Rate})
+
+	report := &FanoutReport{FanoutProgress: FanoutProgress{Cursor: max(fanout.Cursor, 0), Total: len(recipients)}}
+	for report.Cursor < len(recipients) {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		start := report.Cursor
+		group := recipients[start:min(start+window, len(recipients))]
+
+		var (
+			msgs     []SendMessageRequest
+			indexes  []int
+			failures []FanoutFailure
+		)
+		for i, r := range group {
+			req, err := tmpl.request(r, render)
+			if err != nil {
+				failures = append(failures, FanoutFailure{Index: start + i, Recipient: r, Err: err})
+				continue
+			}
+			if err := bucket.wait(ctx); err != nil {
+				return report, err
+			}
+			msgs = append(msgs, *req)
+			indexes = append(indexes, start+i)
+		}
+
+		// end 本组处理到的位置；因 ctx 取消而没有发出的接收者不算处理过，Cursor 停在第一个这样的接收者
+		end := start + len(group)
+		var sent []int
+		if len(msgs) > 0 {
+			// 失败信息逐条记录在结果中，这里不需要合并后的错误
+			res, _ := SendMany(ctx, api, &SendManyRequest{Messages: msgs, ChunkSize: chunkSize, Concurrency: concurrency}, opts...)
+			for _, it := range res.Items {
+				idx := indexes[it.Index]
+				switch {
+				case it.Err == nil:
+					sent = append(sent, idx)
+				case ctx.Err() != nil && errors.Is(it.Err, ctx.Err()):
+					end = min(end, idx)
+				default:
+					failures = append(failures, FanoutFailure{Index: idx, Recipient: recipients[idx], Err: it.Err})
+				}
+			}
+		}
+		// Cursor 之后的结果在续传时会重新发送，这里不计入
+		for _, idx := range sent {
+			if idx < end {
+				report.Sent++
+			}
+		}
+		failures = slices.DeleteFunc(failures, func(f FanoutFailure) bool { return f.Index >= end })
+		slices.SortFunc(failures, func(a, b FanoutFailure) int { return a.Index - b.Index })
+
+		report.Failures = append(report.Failures, failures...)
+		report.Cursor = end
+		report.Failed = len(report.Failures)
+		if fanout.OnProgress != nil {
+			fanout.OnProgress(report.FanoutProgress)
+		}
+		if end < start+len(group) {
+			return report, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+// renderer 返回渲染接收者消息内容的函数
+func (t *FanoutTemplate) renderer() (func(FanoutRecipient) (payload.Content, error), error) {
+	if t.Render != nil {
+		return t.Render, nil
+	}
+	if t.Text == "" {
+		return nil, fmt.Errorf("%w: fanout template requires Text or Render", ErrInvalidMessage)
+	}
+
+	tt, err := template.New("fanout").Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fanout template: %v", ErrInvalidMessage, err)
+	}
+	return func(r FanoutRecipient) (payload.Content, error) {
+		var buf bytes.Buffer
+		if err := tt.Execute(&buf, r); err != nil {
+			return nil, err
+		}
+		return &payload.Text{Content: buf.String()}, nil
+	}, nil
+}
+
+// request 为接收者生成发送到个人频道的请求
+func (t *FanoutTemplate) request(r FanoutRecipient, render func(FanoutRecipient) (payload.Content, error)) (*SendMessageRequest, error) {
+	if r.UID == "" {
+		return nil, fmt.Errorf("%w: recipient uid is required", ErrInvalidMessage)
+	}
+	c, err := render(r)
+	if err != nil {
+		return nil, fmt.Errorf("render for %s: %w", r.UID, err)
+	}
+	p, err := payload.Encode(c)
+	if err != nil {
+		return nil, fmt.Errorf("render for %s: %w", r.UID, err)
+	}
+
+	header := MessageHeader{RedDot: 1}
+	if t.Header != nil {
+		header = *t.Header
+	}
+	clientMsgNo := NewClientMsgNo()
+	if t.ID != "" {
+		clientMsgNo = StableClientMsgNo(t.ID, r.UID)
+	}
+	return &SendMessageRequest{
+		Header:      &header,
+		ClientMsgNo: clientMsgNo,
+		FromUID:     t.FromUID,
+		ChannelID:   r.UID,
+		ChannelType: ChannelTypePerson,
+		Expire:      expireSeconds(t.Expire),
+		Payload:     p,
+		TagKey:      t.TagKey,
+	}, nil
+}
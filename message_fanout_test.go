@@ -0,0 +1,73 @@
+package wukong_go_sdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongmock"
+)
+
+func TestFanoutCancelThenResume(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		delivered = map[string]string{} // ClientMsgNo -> UID，模拟服务端去重
+		cancel    context.CancelFunc
+	)
+	api := wukongmock.NewAPI()
+	api.Message.BatchSendMessageFunc = func(ctx context.Context, req *wukong.BatchSendMessageRequest, _ ...wukong.CallOption) ([]wukong.BatchSendMessageResponseItem, error) {
+		for _, m := range req.Messages {
+			// 第一次发送到 u4 时调用方取消，这个批次没有发出
+			if m.ChannelID == "u4" && cancel != nil {
+				cancel()
+				cancel = nil
+				return nil, ctx.Err()
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var out []wukong.BatchSendMessageResponseItem
+		for _, m := range req.Messages {
+			delivered[m.ClientMsgNo] = m.ChannelID
+			out = append(out, wukong.BatchSendMessageResponseItem{MessageID: 1, MessageSeq: 1, ClientMsgNo: m.ClientMsgNo})
+		}
+		return out, nil
+	}
+
+	var recipients []wukong.FanoutRecipient
+	for i := range 10 {
+		recipients = append(recipients, wukong.FanoutRecipient{UID: fmt.Sprintf("u%d", i)})
+	}
+	tmpl := &wukong.FanoutTemplate{ID: "notice-1", FromUID: "system", Text: "hi {{.UID}}"}
+	opts := wukong.FanoutOptions{ChunkSize: 2, Concurrency: 2}
+
+	ctx, c := context.WithCancel(context.Background())
+	cancel = c
+	report, err := wukong.Fanout(ctx, api.Message, tmpl, recipients, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if report.Cursor != 4 || report.Sent != 4 || report.Failed != 0 {
+		t.Fatalf("report after cancel = %+v, want Cursor 4, Sent 4, Failed 0", report.FanoutProgress)
+	}
+
+	opts.Cursor = report.Cursor
+	report, err = wukong.Fanout(context.Background(), api.Message, tmpl, recipients, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Cursor != 10 || report.Sent != 6 || report.Failed != 0 {
+		t.Errorf("report after resume = %+v, want Cursor 10, Sent 6, Failed 0", report.FanoutProgress)
+	}
+
+	got := map[string]bool{}
+	for _, uid := range delivered {
+		got[uid] = true
+	}
+	if len(delivered) != 10 || len(got) != 10 {
+		t.Errorf("delivered %d messages to %d recipients, want each of the 10 exactly once", len(delivered), len(got))
+	}
+}
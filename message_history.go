@@ -0,0 +1,157 @@
+package wukong_go_sdk
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+)
+
+// defaultHistoryPageSize 历史消息每页默认条数
+const defaultHistoryPageSize = 100
+
+// HistoryOptions 遍历频道历史消息的选项，零值表示从最新消息开始向更早的消息遍历
+type HistoryOptions struct {
+	// LoginUID 当前登录用户，个人频道必填
+	LoginUID string
+	// Direction 遍历方向：PullModeDown 从新到旧，PullModeUp 从旧到新
+	Direction int
+	// StartSeq 起始序号（包含）
+	// PullModeDown 时为 0 表示从最新消息开始；PullModeUp 时为 0 表示从第一条消息开始
+	StartSeq int64
+	// EndSeq 结束序号（包含），0 表示不限制
+	EndSeq int64
+	// PageSize 每次请求拉取的条数，默认 100
+	PageSize int
+}
+
+// IterateHistory 按 seq 分页遍历频道历史消息，自动推进分页窗口，到达 EndSeq 或没有更多消息时结束
+// PullModeDown 按 seq 从大到小返回，PullModeUp 按 seq 从小到大返回
+// 请求失败时产出一次零值消息与错误后结束遍历：
+//
+//	for msg, err := range cli.Message.IterateHistory(ctx, wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}, wukong.HistoryOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *MessageService) IterateHistory(ctx context.Context, channel ChannelKey, hist HistoryOptions, opts ...CallOption) iter.Seq2[Message, error] {
+	return IterateHistory(ctx, s, channel, hist, opts...)
+}
+
+// IterateHistory 通过 api.MessageSync 遍历频道历史消息，行为与 MessageService.IterateHistory 相同
+// api 可以是 Client.Message，也可以是 wukongmock.MessageAPI 等其它实现
+func IterateHistory(ctx context.Context, api MessageAPI, channel ChannelKey, hist HistoryOptions, opts ...CallOption) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		pageSize := hist.PageSize
+		if pageSize <= 0 {
+			pageSize = defaultHistoryPageSize
+		}
+		up := hist.Direction == PullModeUp
+
+		// next 下一页的起始序号；向下拉取时 0 表示从最新消息开始
+		next := hist.StartSeq
+		if up && next < 1 {
+			next = 1
+		}
+
+		for {
+			if hist.EndSeq > 0 && ((up && next > hist.EndSeq) || (!up && next > 0 && next < hist.EndSeq)) {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(Message{}, err)
+				return
+			}
+
+			req := &MessageSyncRequest{
+				LoginUID:        hist.LoginUID,
+				ChannelID:       channel.ChannelID,
+				ChannelType:     channel.ChannelType,
+				StartMessageSeq: next,
+				EndMessageSeq:   syncEndSeq(hist.EndSeq, up),
+				Limit:           pageSize,
+				PullMode:        hist.Direction,
+			}
+			page, err := api.MessageSync(ctx, req, opts...)
+			if err != nil {
+				yield(Message{}, err)
+				return
+			}
+			// 服务端返回不足一页说明已经没有更多消息
+			more := len(page) >= pageSize
+
+			// 服务端对结束序号是否包含的处理不统一，这里放宽请求范围后在本地过滤，并丢弃已经返回过的消息
+			page = slices.DeleteFunc(page, func(m Message) bool {
+				if up {
+					return m.MessageSeq < next || (hist.EndSeq > 0 && m.MessageSeq > hist.EndSeq)
+				}
+				return (next > 0 && m.MessageSeq > next) || m.MessageSeq < hist.EndSeq
+			})
+			if len(page) == 0 {
+				return
+			}
+			slices.SortFunc(page, func(a, b Message) int {
+				if up {
+					return cmp.Compare(a.MessageSeq, b.MessageSeq)
+				}
+				return cmp.Compare(b.MessageSeq, a.MessageSeq)
+			})
+
+			for _, m := range page {
+				if !yield(m, nil) {
+					return
+				}
+			}
+
+			last := page[len(page)-1].MessageSeq
+			if up {
+				next = last + 1
+			} else {
+				next = last - 1
+			}
+			if !more || (!up && next < 1) {
+				return
+			}
+		}
+	}
+}
+
+// History 收集频道历史消息，最多返回 limit 条，limit <= 0 表示不限制
+// 遍历中途失败时返回已经收集到的消息以及错误
+func (s *MessageService) History(ctx context.Context, channel ChannelKey, hist HistoryOptions, limit int, opts ...CallOption) ([]Message, error) {
+	return History(ctx, s, channel, hist, limit, opts...)
+}
+
+// History 通过 api.MessageSync 收集频道历史消息，行为与 MessageService.History 相同
+func History(ctx context.Context, api MessageAPI, channel ChannelKey, hist HistoryOptions, limit int, opts ...CallOption) ([]Message, error) {
+	// 只需要少量消息时避免按默认页大小多拉取
+	if limit > 0 && limit < cmp.Or(hist.PageSize, defaultHistoryPageSize) {
+		hist.PageSize = limit
+	}
+
+	var out []Message
+	for msg, err := range IterateHistory(ctx, api, channel, hist, opts...) {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, msg)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// syncEndSeq 把包含式的结束序号转换为请求参数，向外放宽一位以兼容不包含结束序号的服务端
+func syncEndSeq(end int64, up bool) int64 {
+	switch {
+	case end <= 0:
+		return 0
+	case up:
+		return end + 1
+	case end > 1:
+		return end - 1
+	}
+	return 0
+}
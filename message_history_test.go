@@ -0,0 +1,35 @@
+package wukong_go_sdk_test
+
+import (
+	"context"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongmock"
+)
+
+func TestIterateHistoryWithMock(t *testing.T) {
+	api := wukongmock.NewAPI()
+	api.Message.MessageSyncFunc = func(_ context.Context, req *wukong.MessageSyncRequest, _ ...wukong.CallOption) ([]wukong.Message, error) {
+		if req.PullMode != wukong.PullModeUp {
+			t.Errorf("PullMode = %d, want %d", req.PullMode, wukong.PullModeUp)
+		}
+		var page []wukong.Message
+		for seq := req.StartMessageSeq; seq <= 5 && len(page) < req.Limit; seq++ {
+			page = append(page, wukong.Message{MessageSeq: seq})
+		}
+		return page, nil
+	}
+
+	ch := wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}
+	msgs, err := wukong.History(context.Background(), api.Message, ch, wukong.HistoryOptions{Direction: wukong.PullModeUp, PageSize: 2}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 5 || msgs[0].MessageSeq != 1 || msgs[4].MessageSeq != 5 {
+		t.Errorf("History = %v, want seq 1..5", msgs)
+	}
+	if got := api.Message.CallCount("message.MessageSync"); got != 3 {
+		t.Errorf("MessageSync called %d times, want 3", got)
+	}
+}
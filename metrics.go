@@ -0,0 +1,39 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MetricsRecorder 接收 SDK 调用产生的指标事件，通过 Config.Metrics 开启
+// promwukong 子包提供了基于 Prometheus 的实现
+type MetricsRecorder interface {
+	// OperationStarted 在一次调用开始时回调
+	OperationStarted(name string)
+	// OperationFinished 在一次调用结束时回调
+	// statusCode 为 HTTP 状态码，未拿到响应时为 0；apiStatus 为 APIError.Status，没有时为 0
+	OperationFinished(name string, statusCode, apiStatus int, elapsed time.Duration, err error)
+	// RetryAttempted 在每次重试之前回调，statusCode 为触发重试的那次尝试的状态码
+	RetryAttempted(name string, statusCode int)
+}
+
+// metricsMiddleware 把每次调用的开始、结束事件上报给 MetricsRecorder
+func metricsMiddleware(rec MetricsRecorder) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, op *Operation) error {
+			rec.OperationStarted(op.Name)
+			start := time.Now()
+
+			err := next(ctx, op)
+
+			apiStatus := 0
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				apiStatus = apiErr.Status
+			}
+			rec.OperationFinished(op.Name, op.StatusCode, apiStatus, time.Since(start), err)
+			return err
+		}
+	}
+}
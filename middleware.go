@@ -0,0 +1,109 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"resty.dev/v3"
+)
+
+// Operation 描述一次 SDK 调用，在中间件链中传递
+// 中间件可以读取或修改其中的字段，例如注入请求头、替换请求体
+type Operation struct {
+	// Service 服务名，例如 "message"
+	Service string
+	// Name 操作名，与错误包装中的名称一致，例如 "message.SendMessage"
+	Name string
+
+	Method string
+	// Path 请求路径，不包含查询参数
+	Path string
+	// Query 查询参数，可能为 nil
+	Query url.Values
+	// Endpoint 实际请求的节点地址，由 transport 在发出请求时填充
+	Endpoint string
+
+	// Header 本次调用额外附加的请求头
+	Header http.Header
+
+	// Request 类型化的请求体，例如 *SendMessageRequest，可能为 nil
+	Request any
+	// Response 类型化的响应体指针，调用成功后被填充，可能为 nil
+	Response any
+
+	// StatusCode 服务端返回的 HTTP 状态码，请求未拿到响应时为 0
+	StatusCode int
+	// Attempts 实际发出的请求次数，开启重试时可能大于 1
+	Attempts int
+
+	// resp 最后一次尝试的原始响应，用于 WithResponseCapture
+	resp *resty.Response
+	// retry 本次调用使用的重试策略，默认为 Config.Retry，可以被 WithRetryPolicy 覆盖
+	retry *RetryPolicy
+}
+
+type operationCtxKey struct{}
+
+// OperationFromContext 返回 ctx 所属的 SDK 调用
+// SDK 会把 Operation 放入请求的 ctx 中，中间件与自定义的 http.RoundTripper 都可以通过它拿到操作名等信息
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	op, ok := ctx.Value(operationCtxKey{}).(*Operation)
+	return op, ok
+}
+
+// RoundTrip 执行一次 SDK 调用
+type RoundTrip func(ctx context.Context, op *Operation) error
+
+// Middleware 包装 RoundTrip，用于实现日志、审计、请求改写等横切逻辑
+//
+//	func audit(next wukong.RoundTrip) wukong.RoundTrip {
+//		return func(ctx context.Context, op *wukong.Operation) error {
+//			err := next(ctx, op)
+//			log.Printf("%s status=%d err=%v", op.Name, op.StatusCode, err)
+//			return err
+//		}
+//	}
+type Middleware func(next RoundTrip) RoundTrip
+
+// Chain 把多个中间件按顺序组合为一个，第一个中间件位于最外层
+func Chain(mws ...Middleware) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i] != nil {
+				next = mws[i](next)
+			}
+		}
+		return next
+	}
+}
+
+// Use 追加中间件，之后发起的调用都会经过这些中间件
+// 中间件按注册顺序执行，先注册的位于外层
+func (c *Client) Use(mws ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.middlewares = append(c.middlewares, mws...)
+	c.roundTrip = Chain(c.middlewares...)(c.transport)
+}
+
+// newOperation 根据操作名构造 Operation，Service 取操作名中 "." 之前的部分
+func newOperation(name, method, path string, reqBody, respBody any) *Operation {
+	return &Operation{
+		Service:  serviceOf(name),
+		Name:     name,
+		Method:   method,
+		Path:     path,
+		Header:   http.Header{},
+		Request:  reqBody,
+		Response: respBody,
+	}
+}
+
+// serviceOf 返回操作名中的服务名部分，例如 "message.SendMessage" 返回 "message"
+func serviceOf(name string) string {
+	service, _, _ := strings.Cut(name, ".")
+	return service
+}
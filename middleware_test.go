@@ -0,0 +1,150 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// traceMiddleware 在 next 前后把 name 记录到 trace 中
+func traceMiddleware(trace *[]string, name string) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, op *Operation) error {
+			*trace = append(*trace, name+">")
+			err := next(ctx, op)
+			*trace = append(*trace, "<"+name)
+			return err
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	rt := Chain(traceMiddleware(&trace, "a"), nil, traceMiddleware(&trace, "b"))(func(context.Context, *Operation) error {
+		trace = append(trace, "transport")
+		return nil
+	})
+	if err := rt(context.Background(), &Operation{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a>", "b>", "transport", "<b", "<a"}
+	if !slices.Equal(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+}
+
+func newEchoServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"message_id":7,"message_seq":3,"client_msg_no":"m1"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestClientMiddlewareOrder(t *testing.T) {
+	srv, _ := newEchoServer(t)
+	var trace []string
+	cli := NewClient(Config{
+		BaseURL:     srv.URL,
+		Middlewares: []Middleware{traceMiddleware(&trace, "config")},
+	})
+	defer cli.Close()
+	cli.Use(traceMiddleware(&trace, "use1"), traceMiddleware(&trace, "use2"))
+
+	if _, err := cli.Message.SendMessage(context.Background(), &SendMessageRequest{ClientMsgNo: "m1"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	want := []string{"config>", "use1>", "use2>", "<use2", "<use1", "<config"}
+	if !slices.Equal(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	srv, hits := newEchoServer(t)
+	cli := NewClient(Config{BaseURL: srv.URL})
+	defer cli.Close()
+
+	errDenied := errors.New("denied")
+	var inner bool
+	cli.Use(
+		func(RoundTrip) RoundTrip {
+			return func(context.Context, *Operation) error { return errDenied }
+		},
+		func(next RoundTrip) RoundTrip {
+			return func(ctx context.Context, op *Operation) error {
+				inner = true
+				return next(ctx, op)
+			}
+		},
+	)
+
+	_, err := cli.Message.SendMessage(context.Background(), &SendMessageRequest{ClientMsgNo: "m1"})
+	if !errors.Is(err, errDenied) {
+		t.Fatalf("err = %v, want denied", err)
+	}
+	if inner || hits.Load() != 0 {
+		t.Errorf("inner middleware called = %v, server hits = %d, want neither", inner, hits.Load())
+	}
+}
+
+func TestMiddlewareSeesOperation(t *testing.T) {
+	srv, _ := newEchoServer(t)
+	cli := NewClient(Config{BaseURL: srv.URL})
+	defer cli.Close()
+
+	req := &SendMessageRequest{ClientMsgNo: "m1"}
+	var (
+		before    Operation
+		fromCtx   *Operation
+		after     *SendMessageResponse
+		status    int
+		sameOpCtx bool
+	)
+	cli.Use(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, op *Operation) error {
+			before = *op
+			fromCtx, sameOpCtx = OperationFromContext(ctx)
+			sameOpCtx = sameOpCtx && fromCtx == op
+			err := next(ctx, op)
+			after, _ = op.Response.(*SendMessageResponse)
+			status = op.StatusCode
+			return err
+		}
+	})
+
+	resp, err := cli.Message.SendMessage(context.Background(), req, WithHeader("X-Trace", "t1"))
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if before.Service != "message" || before.Name != "message.SendMessage" ||
+		before.Method != http.MethodPost || before.Path != "/message/send" {
+		t.Errorf("operation = %s %s %s %s", before.Service, before.Name, before.Method, before.Path)
+	}
+	if before.Request != req {
+		t.Errorf("Request = %v, want the caller's request", before.Request)
+	}
+	if got := before.Header.Get("X-Trace"); got != "t1" {
+		t.Errorf("Header X-Trace = %q, want t1", got)
+	}
+	if !sameOpCtx {
+		t.Error("OperationFromContext did not return the operation")
+	}
+	if after == nil || after.MessageID != 7 || after.MessageSeq != 3 || *after != *resp {
+		t.Errorf("Response after next = %+v, want decoded response", after)
+	}
+	if status != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", status)
+	}
+}
@@ -0,0 +1,188 @@
+package wukong_go_sdk
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// ErrInvalidConfig 配置校验失败，由 NewClientE 返回
+var ErrInvalidConfig = errors.New("wukongim: invalid config")
+
+// Option 是 NewClient / NewClientE 的函数式选项，用于定制底层 HTTP 客户端
+type Option func(*clientOptions)
+
+// ConnectionPool 连接池参数，零值字段保持默认
+type ConnectionPool struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+type clientOptions struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	tlsConfig  *tls.Config
+	proxy      string
+	userAgent  string
+	headers    map[string]string
+	pool       *ConnectionPool
+}
+
+// WithHTTPClient 复用已有的 http.Client 及其 Transport，超时仍然由 Config.Timeout 控制
+// SDK 使用 hc 的副本，与 WithTLSConfig 等选项同时使用时会复制 Transport 后再修改，不会影响 hc 的其他使用者
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = hc
+	}
+}
+
+// WithTransport 使用自定义的 http.RoundTripper，例如 mTLS、代理或自定义 DNS 的传输层
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// WithTLSConfig 设置 TLS 配置，要求传输层为 *http.Transport，修改的是它的副本
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithProxy 设置 HTTP 代理地址，例如 "http://proxy:8080"，要求传输层为 *http.Transport
+func WithProxy(proxyURL string) Option {
+	return func(o *clientOptions) {
+		o.proxy = proxyURL
+	}
+}
+
+// WithUserAgent 设置 User-Agent 请求头
+func WithUserAgent(ua string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = ua
+	}
+}
+
+// WithDefaultHeaders 设置每个请求都会携带的请求头
+func WithDefaultHeaders(headers map[string]string) Option {
+	return func(o *clientOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string, len(headers))
+		}
+		for k, v := range headers {
+			o.headers[k] = v
+		}
+	}
+}
+
+// WithConnectionPool 调整连接池参数，要求传输层为 *http.Transport
+func WithConnectionPool(pool ConnectionPool) Option {
+	return func(o *clientOptions) {
+		o.pool = &pool
+	}
+}
+
+// newRestyClient 根据选项创建 resty 客户端
+func (o *clientOptions) newRestyClient() *resty.Client {
+	if o.httpClient != nil {
+		// resty 会修改 http.Client 的 Transport 等字段，使用副本避免影响调用方
+		hc := *o.httpClient
+		return resty.NewWithClient(&hc)
+	}
+	return resty.New()
+}
+
+// apply 把选项应用到 resty 客户端，返回遇到的第一个错误
+func (o *clientOptions) apply(c *resty.Client) error {
+	if o.transport != nil {
+		c.SetTransport(o.transport)
+	}
+
+	if o.tlsConfig != nil || o.proxy != "" || o.pool != nil {
+		rt := c.Transport()
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		base, ok := rt.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("%w: TLS, proxy and connection pool options require *http.Transport, got %T", ErrInvalidConfig, rt)
+		}
+		// 传输层可能来自调用方（WithHTTPClient、WithTransport）或是 http.DefaultTransport，复制后再修改
+		t := base.Clone()
+		c.SetTransport(t)
+
+		if o.tlsConfig != nil {
+			t.TLSClientConfig = o.tlsConfig
+		}
+		if o.proxy != "" {
+			u, err := parseHTTPURL(o.proxy)
+			if err != nil {
+				return fmt.Errorf("%w: proxy: %v", ErrInvalidConfig, err)
+			}
+			t.Proxy = http.ProxyURL(u)
+		}
+		if o.pool != nil {
+			if o.pool.MaxIdleConns > 0 {
+				t.MaxIdleConns = o.pool.MaxIdleConns
+			}
+			if o.pool.MaxIdleConnsPerHost > 0 {
+				t.MaxIdleConnsPerHost = o.pool.MaxIdleConnsPerHost
+			}
+			if o.pool.MaxConnsPerHost > 0 {
+				t.MaxConnsPerHost = o.pool.MaxConnsPerHost
+			}
+			if o.pool.IdleConnTimeout > 0 {
+				t.IdleConnTimeout = o.pool.IdleConnTimeout
+			}
+		}
+	}
+
+	if o.userAgent != "" {
+		c.SetHeader("User-Agent", o.userAgent)
+	}
+	if len(o.headers) > 0 {
+		c.SetHeaders(o.headers)
+	}
+	return nil
+}
+
+// validate 校验配置中的地址
+func (cfg *Config) validate() error {
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 {
+		if cfg.BaseURL == "" {
+			return fmt.Errorf("%w: BaseURL or Endpoints is required", ErrInvalidConfig)
+		}
+		endpoints = []string{cfg.BaseURL}
+	}
+
+	for _, ep := range endpoints {
+		if _, err := parseHTTPURL(ep); err != nil {
+			return fmt.Errorf("%w: endpoint %q: %v", ErrInvalidConfig, ep, err)
+		}
+	}
+	return nil
+}
+
+// parseHTTPURL 解析并校验 http/https 地址
+func parseHTTPURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("missing host")
+	}
+	return u, nil
+}
@@ -0,0 +1,77 @@
+package wukong_go_sdk
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTransportOptionsDoNotMutateSharedClient(t *testing.T) {
+	shared := &http.Transport{MaxIdleConns: 7}
+	hc := &http.Client{Transport: shared, Timeout: time.Minute}
+	tlsCfg := &tls.Config{ServerName: "im.example.com"}
+
+	cli, err := NewClientE(Config{BaseURL: "http://localhost:5001", Timeout: time.Second},
+		WithHTTPClient(hc),
+		WithTLSConfig(tlsCfg),
+		WithProxy("http://proxy.internal:8080"),
+		WithConnectionPool(ConnectionPool{MaxIdleConns: 200, IdleConnTimeout: 30 * time.Second}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hc.Transport != shared || hc.Timeout != time.Minute {
+		t.Errorf("caller's http.Client was modified: %+v", hc)
+	}
+	if shared.TLSClientConfig == tlsCfg || shared.Proxy != nil || shared.MaxIdleConns != 7 || shared.IdleConnTimeout != 0 {
+		t.Errorf("caller's transport was modified: tls=%t proxy=%t maxIdle=%d idle=%s",
+			shared.TLSClientConfig == tlsCfg, shared.Proxy != nil, shared.MaxIdleConns, shared.IdleConnTimeout)
+	}
+
+	got, err := cli.cli.HTTPTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == shared {
+		t.Fatal("client uses the caller's transport instead of a copy")
+	}
+	if got.TLSClientConfig != tlsCfg || got.MaxIdleConns != 200 || got.IdleConnTimeout != 30*time.Second {
+		t.Errorf("options not applied: tls=%v maxIdle=%d idle=%s", got.TLSClientConfig, got.MaxIdleConns, got.IdleConnTimeout)
+	}
+	proxy, err := got.Proxy(&http.Request{URL: &url.URL{Scheme: "http", Host: "localhost:5001"}})
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:8080" {
+		t.Errorf("proxy = %v, %v", proxy, err)
+	}
+}
+
+func TestTransportOptionsDoNotMutateDefaultTransport(t *testing.T) {
+	def := http.DefaultTransport.(*http.Transport)
+
+	_, err := NewClientE(Config{BaseURL: "http://localhost:5001"},
+		WithTransport(http.DefaultTransport),
+		WithTLSConfig(&tls.Config{ServerName: "im.example.com"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 标准库初始化 HTTP/2 时可能会为 DefaultTransport 填充 TLSClientConfig，这里只检查 SDK 的设置没有写进去
+	if def.TLSClientConfig != nil && def.TLSClientConfig.ServerName == "im.example.com" {
+		t.Error("http.DefaultTransport was modified")
+	}
+}
+
+func TestTransportOptionsRequireHTTPTransport(t *testing.T) {
+	rt := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+	_, err := NewClientE(Config{BaseURL: "http://localhost:5001"}, WithTransport(rt), WithProxy("http://proxy:8080"))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("err = %v, want ErrInvalidConfig", err)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,183 @@
+// Package otelwukong 为 WuKongIM Go SDK 提供 OpenTelemetry 链路追踪与指标埋点
+//
+// 通过 Config.Middlewares 接入后，所有服务调用都会自动生成 span 并上报指标：
+//
+//	cli := wukong.NewClient(wukong.Config{
+//		BaseURL:     "http://localhost:5001",
+//		Middlewares: []wukong.Middleware{otelwukong.Middleware()},
+//	})
+package otelwukong
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName 是 tracer 与 meter 使用的 instrumentation scope 名称
+const ScopeName = "github.com/linabellbiu/wukong-go-sdk/otelwukong"
+
+// 属性键
+const (
+	AttrOperation   = attribute.Key("wukongim.operation")
+	AttrService     = attribute.Key("wukongim.service")
+	AttrChannelID   = attribute.Key("wukongim.channel_id")
+	AttrChannelType = attribute.Key("wukongim.channel_type")
+	AttrUID         = attribute.Key("wukongim.uid")
+	AttrMethod      = attribute.Key("http.request.method")
+	AttrPath        = attribute.Key("url.path")
+	AttrStatusCode  = attribute.Key("http.response.status_code")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option 配置埋点中间件
+type Option func(*config)
+
+// WithTracerProvider 指定 TracerProvider，默认使用 otel.GetTracerProvider()
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider 指定 MeterProvider，默认使用 otel.GetMeterProvider()
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithPropagator 指定向请求头注入链路上下文的传播器，默认使用 otel.GetTextMapPropagator()
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+// Middleware 返回一个 OpenTelemetry 埋点中间件
+//
+// 每个服务操作生成一个 span，名称与错误包装中的操作名一致（例如 conversation.Sync），
+// 并记录 channel_id / channel_type / uid 等属性；同时上报
+// wukongim.client.duration 耗时直方图和 wukongim.client.errors 错误计数，
+// 按操作名与 HTTP 状态码区分。
+func Middleware(opts ...Option) wukong.Middleware {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otel.GetTracerProvider()
+	}
+	if cfg.meterProvider == nil {
+		cfg.meterProvider = otel.GetMeterProvider()
+	}
+	if cfg.propagator == nil {
+		cfg.propagator = otel.GetTextMapPropagator()
+	}
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("wukongim.client.duration",
+		metric.WithDescription("WuKongIM SDK 调用耗时"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	errorCount, err := meter.Int64Counter("wukongim.client.errors",
+		metric.WithDescription("WuKongIM SDK 调用失败次数"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next wukong.RoundTrip) wukong.RoundTrip {
+		return func(ctx context.Context, op *wukong.Operation) error {
+			attrs := []attribute.KeyValue{
+				AttrOperation.String(op.Name),
+				AttrService.String(op.Service),
+				AttrMethod.String(op.Method),
+				AttrPath.String(op.Path),
+			}
+
+			ctx, span := tracer.Start(ctx, op.Name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+				trace.WithAttributes(requestAttributes(op.Request)...),
+			)
+			defer span.End()
+
+			cfg.propagator.Inject(ctx, propagation.HeaderCarrier(op.Header))
+
+			start := time.Now()
+			err := next(ctx, op)
+			elapsed := time.Since(start)
+
+			metricAttrs := metric.WithAttributes(
+				AttrOperation.String(op.Name),
+				AttrStatusCode.Int(op.StatusCode),
+			)
+			if duration != nil {
+				duration.Record(ctx, elapsed.Seconds(), metricAttrs)
+			}
+
+			if op.StatusCode != 0 {
+				span.SetAttributes(AttrStatusCode.Int(op.StatusCode))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				if errorCount != nil {
+					errorCount.Add(ctx, 1, metricAttrs)
+				}
+			}
+			return err
+		}
+	}
+}
+
+// requestAttributes 从类型化的请求体中提取 channel_id / channel_type / uid 属性
+// 请求体字段命名在各服务中保持一致，这里按字段名读取
+func requestAttributes(req any) []attribute.KeyValue {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if f := v.FieldByName("ChannelID"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+		attrs = append(attrs, AttrChannelID.String(f.String()))
+	}
+	if f := v.FieldByName("ChannelType"); f.IsValid() && f.CanInt() && f.Int() != 0 {
+		attrs = append(attrs, AttrChannelType.Int64(f.Int()))
+	}
+	for _, name := range []string{"UID", "FromUID", "LoginUID"} {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			attrs = append(attrs, AttrUID.String(f.String()))
+			break
+		}
+	}
+	return attrs
+}
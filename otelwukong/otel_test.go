@@ -0,0 +1,141 @@
+package otelwukong_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/otelwukong"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newClient(t *testing.T, srv *wukongtest.Server) (*wukong.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+	})
+
+	cli := srv.Client(wukong.Config{
+		Middlewares: []wukong.Middleware{otelwukong.Middleware(
+			otelwukong.WithTracerProvider(tp),
+			otelwukong.WithMeterProvider(mp),
+			otelwukong.WithPropagator(propagation.TraceContext{}),
+		)},
+	})
+	return cli, exporter, reader
+}
+
+func TestMiddlewareRecordsSpan(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli, exporter, _ := newClient(t, srv)
+
+	_, err := cli.Message.SendMessage(context.Background(), &wukong.SendMessageRequest{
+		FromUID:     "u1",
+		ChannelID:   "u2",
+		ChannelType: wukong.ChannelTypePerson,
+		Payload:     "aGk=",
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "message.SendMessage" {
+		t.Errorf("span name = %q, want message.SendMessage", span.Name)
+	}
+	want := map[attribute.Key]attribute.Value{
+		otelwukong.AttrOperation:   attribute.StringValue("message.SendMessage"),
+		otelwukong.AttrService:     attribute.StringValue("message"),
+		otelwukong.AttrChannelID:   attribute.StringValue("u2"),
+		otelwukong.AttrChannelType: attribute.Int64Value(int64(wukong.ChannelTypePerson)),
+		otelwukong.AttrUID:         attribute.StringValue("u1"),
+		otelwukong.AttrStatusCode:  attribute.IntValue(http.StatusOK),
+	}
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("attribute %s = %v, want %v", k, got[k].Emit(), v.Emit())
+		}
+	}
+	if span.Status.Code == codes.Error {
+		t.Errorf("span status = %v, want unset", span.Status)
+	}
+
+	req, ok := srv.LastRequest("/message/send")
+	if !ok {
+		t.Fatal("no request recorded")
+	}
+	if req.Header.Get("Traceparent") == "" {
+		t.Error("traceparent header was not injected")
+	}
+}
+
+func TestMiddlewareRecordsErrors(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli, exporter, reader := newClient(t, srv)
+
+	srv.InjectFault(wukongtest.Fault{Path: "/channel/delete", Status: http.StatusInternalServerError})
+	_, err := cli.Channel.Delete(context.Background(), &wukong.DeleteChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("span status = %v, want error", spans[0].Status.Code)
+	}
+	if len(spans[0].Events) == 0 || spans[0].Events[0].Name != "exception" {
+		t.Errorf("error was not recorded on the span: %+v", spans[0].Events)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	var durations, errs int
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "wukongim.client.duration":
+				for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					durations += int(dp.Count)
+				}
+			case "wukongim.client.errors":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					errs += int(dp.Value)
+					if v, _ := dp.Attributes.Value(otelwukong.AttrStatusCode); v.AsInt64() != http.StatusInternalServerError {
+						t.Errorf("error status code = %v, want 500", v.Emit())
+					}
+				}
+			}
+		}
+	}
+	if durations != 1 || errs != 1 {
+		t.Errorf("durations = %d, errors = %d, want 1 and 1", durations, errs)
+	}
+}
@@ -0,0 +1,302 @@
+// Package outbox 提供可靠的消息发件箱：先把待发送的消息写入持久化存储，再由后台 Dispatcher 投递
+//
+// 进程在决定发送与 SendMessage 返回之间崩溃时，消息仍然保存在 Store 中，重启后继续投递；
+// 每条消息的 ClientMsgNo 在入队时确定并在重试中保持不变，由服务端去重，
+// 因此投递是至少一次（at-least-once），结合服务端去重实际效果为恰好一次：
+//
+//	store, err := outbox.NewFileStore("/var/lib/myapp/outbox")
+//	if err != nil {
+//		return err
+//	}
+//	d := outbox.NewDispatcher(cli.Message, store,
+//		outbox.WithMaxAttempts(8),
+//		outbox.WithOnDead(func(e outbox.Entry, err error) { log.Printf("dead letter %s: %v", e.ID, err) }),
+//	)
+//	go d.Run(ctx)
+//
+//	id, err := d.Enqueue(ctx, req)
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// Dispatcher 的默认配置，对应选项传入 <= 0 时同样使用默认值
+const (
+	defaultMaxAttempts  = 10
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+// Option 配置 Dispatcher
+type Option func(*Dispatcher)
+
+// WithMaxAttempts 最多尝试发送的次数，达到后进入死信，默认 10，<= 0 时使用默认值
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithBackoff 自定义第 attempt 次（从 1 开始）失败后等待多久再重试
+// 默认从 1s 开始指数增长，最长 5min，并带有随机抖动，为 nil 时使用默认值
+func WithBackoff(backoff func(attempt int) time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.backoff = backoff
+	}
+}
+
+// WithPollInterval 没有新消息入队时检查到期消息的间隔，默认 1s，<= 0 时使用默认值
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+// WithBatchSize 每轮最多处理的消息数，默认 100，<= 0 时使用默认值
+func WithBatchSize(n int) Option {
+	return func(d *Dispatcher) {
+		d.batchSize = n
+	}
+}
+
+// WithPermanent 判断错误是否不可恢复，不可恢复的消息直接进入死信而不再重试
+// 默认 400、403、404 视为不可恢复，为 nil 时使用默认值
+func WithPermanent(permanent func(err error) bool) Option {
+	return func(d *Dispatcher) {
+		d.permanent = permanent
+	}
+}
+
+// WithOnSuccess 消息投递成功后回调
+func WithOnSuccess(fn func(e Entry, resp *wukong.SendMessageResponse)) Option {
+	return func(d *Dispatcher) {
+		d.onSuccess = fn
+	}
+}
+
+// WithOnFailure 消息发送失败、等待重试时回调，e.NextAttempt 为下一次重试的时间
+func WithOnFailure(fn func(e Entry, err error)) Option {
+	return func(d *Dispatcher) {
+		d.onFailure = fn
+	}
+}
+
+// WithOnDead 消息进入死信时回调
+func WithOnDead(fn func(e Entry, err error)) Option {
+	return func(d *Dispatcher) {
+		d.onDead = fn
+	}
+}
+
+// WithOnError 访问 Store 出错时回调，Run 会在下一轮继续尝试
+func WithOnError(fn func(err error)) Option {
+	return func(d *Dispatcher) {
+		d.onError = fn
+	}
+}
+
+// Dispatcher 从 Store 中取出到期的消息并通过 SendMessage 投递
+type Dispatcher struct {
+	api   wukong.MessageAPI
+	store Store
+
+	maxAttempts  int
+	backoff      func(attempt int) time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	permanent    func(err error) bool
+
+	onSuccess func(e Entry, resp *wukong.SendMessageResponse)
+	onFailure func(e Entry, err error)
+	onDead    func(e Entry, err error)
+	onError   func(err error)
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+// NewDispatcher 创建 Dispatcher，api 通常为 Client.Message
+func NewDispatcher(api wukong.MessageAPI, store Store, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		api:          api,
+		store:        store,
+		maxAttempts:  defaultMaxAttempts,
+		backoff:      defaultBackoff,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		permanent:    defaultPermanent,
+		wake:         make(chan struct{}, 1),
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = defaultMaxAttempts
+	}
+	if d.pollInterval <= 0 {
+		d.pollInterval = defaultPollInterval
+	}
+	if d.batchSize <= 0 {
+		d.batchSize = defaultBatchSize
+	}
+	if d.backoff == nil {
+		d.backoff = defaultBackoff
+	}
+	if d.permanent == nil {
+		d.permanent = defaultPermanent
+	}
+	return d
+}
+
+// Enqueue 把消息写入 Store 并唤醒 Run，返回消息 ID（即 ClientMsgNo）
+// 未指定 ClientMsgNo 时自动生成并写回 req；ClientMsgNo 已经入队时不会重复入队
+func (d *Dispatcher) Enqueue(ctx context.Context, req *wukong.SendMessageRequest) (string, error) {
+	if req == nil {
+		return "", errors.New("outbox: nil request")
+	}
+	if req.ClientMsgNo == "" {
+		req.ClientMsgNo = wukong.NewClientMsgNo()
+	}
+
+	now := d.now()
+	e := Entry{
+		ID:          req.ClientMsgNo,
+		Request:     *req,
+		NextAttempt: now,
+		CreatedAt:   now,
+	}
+	if err := d.store.Put(ctx, e); err != nil {
+		return "", fmt.Errorf("outbox: enqueue %s: %w", e.ID, err)
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return e.ID, nil
+}
+
+// Run 持续投递到期的消息，直到 ctx 结束，返回 ctx.Err()
+// 同一个 Store 同一时刻只应有一个 Dispatcher 在运行
+func (d *Dispatcher) Run(ctx context.Context) error {
+	t := time.NewTicker(d.pollInterval)
+	defer t.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			n, err := d.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil && d.onError != nil {
+				d.onError(err)
+			}
+			// 一轮处理满 batchSize 条时说明可能还有到期的消息，立即继续
+			if err != nil || n < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue 处理一轮到期的消息，返回本轮处理（成功、等待重试或进入死信）的消息数
+// 适合在定时任务中代替 Run 使用
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	entries, err := d.store.Due(ctx, d.now(), d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("outbox: load due entries: %w", err)
+	}
+
+	for i, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		if err := d.deliver(ctx, e); err != nil {
+			return i, err
+		}
+	}
+	return len(entries), nil
+}
+
+// Requeue 把死信重新放回发件箱，重置尝试次数并立即投递，ClientMsgNo 保持不变
+func (d *Dispatcher) Requeue(ctx context.Context, e Entry) error {
+	e.Attempts = 0
+	e.DeadAt = time.Time{}
+	e.NextAttempt = d.now()
+	e.LastError = ""
+	if err := d.store.Update(ctx, e); err != nil {
+		return fmt.Errorf("outbox: requeue %s: %w", e.ID, err)
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// deliver 发送一条消息并更新它的状态，只有访问 Store 失败时返回错误
+func (d *Dispatcher) deliver(ctx context.Context, e Entry) error {
+	req := e.Request
+	resp, sendErr := d.api.SendMessage(ctx, &req)
+	if sendErr == nil {
+		// 删除失败时消息会被再次发送，由服务端按 ClientMsgNo 去重
+		if err := d.store.Delete(ctx, e.ID); err != nil {
+			return fmt.Errorf("outbox: delete delivered %s: %w", e.ID, err)
+		}
+		if d.onSuccess != nil {
+			d.onSuccess(e, resp)
+		}
+		return nil
+	}
+
+	// ctx 结束导致的失败不计入尝试次数
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	e.Attempts++
+	e.LastError = sendErr.Error()
+	if d.permanent(sendErr) || e.Attempts >= d.maxAttempts {
+		e.DeadAt = d.now()
+		if err := d.store.Update(ctx, e); err != nil {
+			return fmt.Errorf("outbox: bury %s: %w", e.ID, err)
+		}
+		if d.onDead != nil {
+			d.onDead(e, sendErr)
+		}
+		return nil
+	}
+
+	e.NextAttempt = d.now().Add(d.backoff(e.Attempts))
+	if err := d.store.Update(ctx, e); err != nil {
+		return fmt.Errorf("outbox: update %s: %w", e.ID, err)
+	}
+	if d.onFailure != nil {
+		d.onFailure(e, sendErr)
+	}
+	return nil
+}
+
+// defaultBackoff 1s、2s、4s……最长 5min，并在 [d/2, d) 之间随机抖动
+func defaultBackoff(attempt int) time.Duration {
+	d := time.Second << min(attempt-1, 9)
+	d = min(d, 5*time.Minute)
+	return d/2 + rand.N(d/2)
+}
+
+func defaultPermanent(err error) bool {
+	return errors.Is(err, wukong.ErrBadRequest) || errors.Is(err, wukong.ErrForbidden) || errors.Is(err, wukong.ErrNotFound)
+}
@@ -0,0 +1,134 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/outbox"
+	"github.com/linabellbiu/wukong-go-sdk/wukongmock"
+)
+
+func TestDispatcherZeroOptionsUseDefaults(t *testing.T) {
+	api := wukongmock.NewAPI()
+	api.Message.SendMessageFunc = func(context.Context, *wukong.SendMessageRequest, ...wukong.CallOption) (*wukong.SendMessageResponse, error) {
+		return nil, errors.New("unavailable")
+	}
+	store := outbox.NewMemoryStore()
+	d := outbox.NewDispatcher(api.Message, store,
+		outbox.WithBatchSize(0),
+		outbox.WithPollInterval(0),
+		outbox.WithMaxAttempts(0),
+		outbox.WithBackoff(func(int) time.Duration { return time.Hour }),
+	)
+
+	ctx := context.Background()
+	id, err := d.Enqueue(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == "" {
+		t.Fatal("Enqueue returned an empty id")
+	}
+
+	// 批次大小为 0 时 Run 曾经忙等且不检查 ctx，轮询间隔为 0 时 time.NewTicker 会 panic
+	runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- d.Run(runCtx) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Run returned %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after ctx ended")
+	}
+
+	// 尝试次数为 0 时第一次失败曾经直接进入死信
+	dead, err := store.Dead(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 0 {
+		t.Errorf("%d entries dead after one failure, want 0", len(dead))
+	}
+}
+
+func TestDispatcherNilFuncOptionsUseDefaults(t *testing.T) {
+	api := wukongmock.NewAPI()
+	api.Message.SendMessageFunc = func(_ context.Context, req *wukong.SendMessageRequest, _ ...wukong.CallOption) (*wukong.SendMessageResponse, error) {
+		if req.ClientMsgNo == "missing" {
+			return nil, &wukong.APIError{HttpCode: 404}
+		}
+		return nil, errors.New("unavailable")
+	}
+	store := outbox.NewMemoryStore()
+	var failed []outbox.Entry
+	d := outbox.NewDispatcher(api.Message, store,
+		outbox.WithBackoff(nil),
+		outbox.WithPermanent(nil),
+		outbox.WithOnFailure(func(e outbox.Entry, _ error) { failed = append(failed, e) }),
+	)
+
+	ctx := context.Background()
+	for _, no := range []string{"flaky", "missing"} {
+		if _, err := d.Enqueue(ctx, &wukong.SendMessageRequest{ClientMsgNo: no, FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// nil 曾经被原样保存，第一次失败时调用 nil 函数导致 panic
+	start := time.Now()
+	if _, err := d.DeliverDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 默认退避：第一次失败后等待 [0.5s, 1s)
+	if len(failed) != 1 || failed[0].ID != "flaky" {
+		t.Fatalf("failed = %+v, want only flaky", failed)
+	}
+	if wait := failed[0].NextAttempt.Sub(start); wait < 500*time.Millisecond || wait > time.Second+100*time.Millisecond {
+		t.Errorf("next attempt in %v, want the default 0.5s-1s backoff", wait)
+	}
+
+	// 默认不可恢复判断：404 直接进入死信
+	dead, err := store.Dead(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].ID != "missing" {
+		t.Errorf("dead = %+v, want only missing", dead)
+	}
+}
+
+func TestRunStopsDrainingWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sent atomic.Int32
+	api := wukongmock.NewAPI()
+	api.Message.SendMessageFunc = func(_ context.Context, req *wukong.SendMessageRequest, _ ...wukong.CallOption) (*wukong.SendMessageResponse, error) {
+		// 第一条投递成功后调用方取消
+		sent.Add(1)
+		cancel()
+		return &wukong.SendMessageResponse{MessageID: 1, MessageSeq: 1, ClientMsgNo: req.ClientMsgNo}, nil
+	}
+	store := outbox.NewMemoryStore()
+	d := outbox.NewDispatcher(api.Message, store, outbox.WithBatchSize(1))
+	for range 5 {
+		if _, err := d.Enqueue(context.Background(), &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := d.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run returned %v, want context.Canceled", err)
+	}
+	if n := sent.Load(); n != 1 {
+		t.Errorf("sent %d messages after cancel, want 1", n)
+	}
+}
@@ -0,0 +1,288 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// ErrNotFound Store 中没有指定 ID 的消息
+var ErrNotFound = errors.New("outbox: entry not found")
+
+// Entry 发件箱中的一条消息
+type Entry struct {
+	// ID 等于 Request.ClientMsgNo，服务端以此去重
+	ID      string                    `json:"id"`
+	Request wukong.SendMessageRequest `json:"request"`
+	// Attempts 已经尝试发送的次数
+	Attempts int `json:"attempts"`
+	// NextAttempt 下一次可以发送的时间
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// DeadAt 进入死信的时间，零值表示仍在等待发送
+	DeadAt time.Time `json:"dead_at,omitempty"`
+}
+
+// Dead 是否已经进入死信
+func (e Entry) Dead() bool {
+	return !e.DeadAt.IsZero()
+}
+
+// Store 发件箱的持久化存储，实现需要支持并发调用
+//
+// 基于 SQL 的实现可以使用类似下面的表结构，Request 以 JSON 保存：
+//
+//	CREATE TABLE wukong_outbox (
+//		id           VARCHAR(64) PRIMARY KEY,
+//		request      TEXT        NOT NULL,
+//		attempts     INT         NOT NULL DEFAULT 0,
+//		next_attempt TIMESTAMP   NOT NULL,
+//		last_error   TEXT,
+//		created_at   TIMESTAMP   NOT NULL,
+//		dead_at      TIMESTAMP   NULL
+//	);
+//	CREATE INDEX wukong_outbox_due ON wukong_outbox (dead_at, next_attempt, created_at);
+type Store interface {
+	// Put 保存新消息，ID 已经存在时不做任何修改并返回 nil，使重复入队是幂等的
+	Put(ctx context.Context, e Entry) error
+	// Due 按 CreatedAt 顺序返回最多 limit 条未进入死信且 NextAttempt 不晚于 now 的消息
+	Due(ctx context.Context, now time.Time, limit int) ([]Entry, error)
+	// Update 按 ID 覆盖消息的状态，ID 不存在时返回 ErrNotFound
+	Update(ctx context.Context, e Entry) error
+	// Delete 删除消息，ID 不存在时返回 nil
+	Delete(ctx context.Context, id string) error
+	// Dead 按 CreatedAt 顺序返回所有死信
+	Dead(ctx context.Context) ([]Entry, error)
+}
+
+// entryIndex 内存中的消息索引，MemoryStore 与 FileStore 共用
+type entryIndex struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+func (x *entryIndex) due(now time.Time, limit int) []Entry {
+	return x.filter(limit, func(e Entry) bool { return !e.Dead() && !e.NextAttempt.After(now) })
+}
+
+func (x *entryIndex) dead() []Entry {
+	return x.filter(0, Entry.Dead)
+}
+
+// filter 按 CreatedAt、ID 排序后返回满足条件的消息，limit <= 0 表示不限制，调用方需持有锁
+func (x *entryIndex) filter(limit int, keep func(Entry) bool) []Entry {
+	var out []Entry
+	for _, e := range x.entries {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	slices.SortFunc(out, func(a, b Entry) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// MemoryStore 基于内存的 Store，进程退出后数据丢失，只适合测试
+type MemoryStore struct {
+	idx entryIndex
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{idx: entryIndex{entries: map[string]Entry{}}}
+}
+
+// Put 实现 Store
+func (s *MemoryStore) Put(_ context.Context, e Entry) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	if _, ok := s.idx.entries[e.ID]; !ok {
+		s.idx.entries[e.ID] = e
+	}
+	return nil
+}
+
+// Due 实现 Store
+func (s *MemoryStore) Due(_ context.Context, now time.Time, limit int) ([]Entry, error) {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	return s.idx.due(now, limit), nil
+}
+
+// Update 实现 Store
+func (s *MemoryStore) Update(_ context.Context, e Entry) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	if _, ok := s.idx.entries[e.ID]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, e.ID)
+	}
+	s.idx.entries[e.ID] = e
+	return nil
+}
+
+// Delete 实现 Store
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	delete(s.idx.entries, id)
+	return nil
+}
+
+// Dead 实现 Store
+func (s *MemoryStore) Dead(_ context.Context) ([]Entry, error) {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	return s.idx.dead(), nil
+}
+
+// FileStore 基于文件的 Store，不依赖 bolt、SQLite 等外部组件
+// 每条消息保存为 dir 下的一个 JSON 文件，写入时先 fsync 临时文件再重命名，进程崩溃不会留下不完整的消息；
+// 打开时把所有消息载入内存索引，因此同一个目录只能由一个进程使用
+type FileStore struct {
+	dir string
+	idx entryIndex
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore 打开（或创建）dir 下的文件存储
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("outbox: open %s: %w", dir, err)
+	}
+
+	s := &FileStore{dir: dir, idx: entryIndex{entries: map[string]Entry{}}}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("outbox: open %s: %w", dir, err)
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("outbox: decode %s: %w", name, err)
+		}
+		s.idx.entries[e.ID] = e
+	}
+	return s, nil
+}
+
+// Put 实现 Store
+func (s *FileStore) Put(_ context.Context, e Entry) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	if _, ok := s.idx.entries[e.ID]; ok {
+		return nil
+	}
+	if err := s.write(e); err != nil {
+		return err
+	}
+	s.idx.entries[e.ID] = e
+	return nil
+}
+
+// Due 实现 Store
+func (s *FileStore) Due(_ context.Context, now time.Time, limit int) ([]Entry, error) {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	return s.idx.due(now, limit), nil
+}
+
+// Update 实现 Store
+func (s *FileStore) Update(_ context.Context, e Entry) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	if _, ok := s.idx.entries[e.ID]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, e.ID)
+	}
+	if err := s.write(e); err != nil {
+		return err
+	}
+	s.idx.entries[e.ID] = e
+	return nil
+}
+
+// Delete 实现 Store
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("outbox: delete %s: %w", id, err)
+	}
+	delete(s.idx.entries, id)
+	return nil
+}
+
+// Dead 实现 Store
+func (s *FileStore) Dead(_ context.Context) ([]Entry, error) {
+	s.idx.mu.Lock()
+	defer s.idx.mu.Unlock()
+	return s.idx.dead(), nil
+}
+
+// path 消息文件的路径，ID 可能包含任意字符，因此使用哈希作为文件名
+func (s *FileStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// write 原子地写入消息文件，调用方需持有锁
+func (s *FileStore) write(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("outbox: write %s: %w", e.ID, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("outbox: write %s: %w", e.ID, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("outbox: write %s: %w", e.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("outbox: write %s: %w", e.ID, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(e.ID)); err != nil {
+		return fmt.Errorf("outbox: write %s: %w", e.ID, err)
+	}
+	syncDir(s.dir)
+	return nil
+}
+
+// syncDir 尽量把目录项的变化落盘，部分平台不支持对目录 fsync，忽略错误
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+}
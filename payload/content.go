@@ -0,0 +1,151 @@
+package payload
+
+import "encoding/json"
+
+// Mention 文本消息中的 @ 信息
+type Mention struct {
+	// All 为 1 表示 @所有人
+	All  int      `json:"all,omitempty"`
+	UIDs []string `json:"uids,omitempty"`
+}
+
+// Reply 回复信息，指向被回复的消息
+type Reply struct {
+	MessageID  string `json:"message_id"`
+	MessageSeq int64  `json:"message_seq"`
+	FromUID    string `json:"from_uid"`
+	FromName   string `json:"from_name,omitempty"`
+	// RootMID 回复链中第一条消息的 ID
+	RootMID string `json:"root_mid,omitempty"`
+	// Payload 被回复消息的内容 JSON
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Text 文本消息
+type Text struct {
+	Content string   `json:"content"`
+	Mention *Mention `json:"mention,omitempty"`
+	Reply   *Reply   `json:"reply,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Text) ContentType() ContentType { return TypeText }
+
+// Image 图片消息
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Image) ContentType() ContentType { return TypeImage }
+
+// GIF 动图消息
+type GIF struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*GIF) ContentType() ContentType { return TypeGIF }
+
+// Voice 语音消息
+type Voice struct {
+	URL string `json:"url"`
+	// Duration 语音时长（秒）
+	Duration int `json:"timeTrad"`
+	// Waveform 波形数据（base64）
+	Waveform string `json:"waveform,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Voice) ContentType() ContentType { return TypeVoice }
+
+// Video 视频消息
+type Video struct {
+	URL   string `json:"url"`
+	Cover string `json:"cover,omitempty"`
+	// Size 文件大小（字节）
+	Size   int64 `json:"size,omitempty"`
+	Width  int   `json:"width,omitempty"`
+	Height int   `json:"height,omitempty"`
+	// Second 视频时长（秒）
+	Second int `json:"second,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Video) ContentType() ContentType { return TypeVideo }
+
+// Location 位置消息
+type Location struct {
+	Title     string  `json:"title"`
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+	// Img 位置截图地址
+	Img string `json:"img,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Location) ContentType() ContentType { return TypeLocation }
+
+// Card 名片消息
+type Card struct {
+	UID     string `json:"uid"`
+	Name    string `json:"name"`
+	Avatar  string `json:"avatar,omitempty"`
+	Vercode string `json:"vercode,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Card) ContentType() ContentType { return TypeCard }
+
+// File 文件消息
+type File struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+	// Size 文件大小（字节）
+	Size int64 `json:"size,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*File) ContentType() ContentType { return TypeFile }
+
+// Revoke 撤回消息，指向被撤回的消息
+type Revoke struct {
+	MessageID   string `json:"message_id"`
+	ClientMsgNo string `json:"client_msg_no,omitempty"`
+}
+
+// ContentType 实现 Content
+func (*Revoke) ContentType() ContentType { return TypeRevoke }
+
+// System 系统通知，例如群创建、成员加入/移除等，类型在 TypeSystemMin 与 TypeSystemMax 之间
+// Content 中可以使用 {0}、{1} 等占位符，由 Extra 中的对应项填充
+type System struct {
+	Type    ContentType `json:"type"`
+	Content string      `json:"content"`
+	Extra   []any       `json:"extra,omitempty"`
+}
+
+// ContentType 实现 Content
+func (s *System) ContentType() ContentType { return s.Type }
+
+// Generic 未注册类型的内容，保留原始 JSON 字段
+type Generic map[string]any
+
+// ContentType 返回 type 字段的值
+func (g Generic) ContentType() ContentType {
+	switch t := g["type"].(type) {
+	case float64:
+		return ContentType(t)
+	case json.Number:
+		n, _ := t.Int64()
+		return ContentType(n)
+	case int:
+		return ContentType(t)
+	}
+	return 0
+}
@@ -0,0 +1,168 @@
+// Package payload 提供 WuKong 标准消息内容的类型化编解码
+//
+// WuKongIM 的消息 Payload 是 base64 编码的字节，业务中通常使用 WuKong 标准的内容 JSON，
+// 例如 {"type":1,"content":"hi"}。Encode 把类型化的内容编码为可以直接放入 Payload 的 base64 字符串，
+// Decode 则按 type 字段还原为对应的结构体：
+//
+//	p, _ := payload.Encode(&payload.Text{Content: "hi"})
+//	c, _ := payload.Decode(p)
+//	if text, ok := c.(*payload.Text); ok { ... }
+//
+// 自定义类型通过 Register 注册；未注册的系统消息（1000-2000）解码为 *System，其它未知类型解码为 Generic
+package payload
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidPayload Payload 既不是 base64 编码的 JSON，也不是 JSON 对象
+var ErrInvalidPayload = errors.New("payload: invalid payload")
+
+// ContentType 消息内容类型，对应内容 JSON 中的 type 字段
+type ContentType int
+
+const (
+	// TypeText 文本
+	TypeText ContentType = 1
+	// TypeImage 图片
+	TypeImage ContentType = 2
+	// TypeGIF GIF 动图
+	TypeGIF ContentType = 3
+	// TypeVoice 语音
+	TypeVoice ContentType = 4
+	// TypeVideo 视频
+	TypeVideo ContentType = 5
+	// TypeLocation 位置
+	TypeLocation ContentType = 6
+	// TypeCard 名片
+	TypeCard ContentType = 7
+	// TypeFile 文件
+	TypeFile ContentType = 8
+
+	// TypeRevoke 撤回消息
+	TypeRevoke ContentType = 1006
+
+	// TypeSystemMin / TypeSystemMax 系统消息的类型范围，例如群创建、成员变更等通知
+	TypeSystemMin ContentType = 1000
+	TypeSystemMax ContentType = 2000
+)
+
+// IsSystem 判断是否为系统消息类型
+func (t ContentType) IsSystem() bool {
+	return t >= TypeSystemMin && t <= TypeSystemMax
+}
+
+// Content 类型化的消息内容
+type Content interface {
+	ContentType() ContentType
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[ContentType]func() Content{}
+)
+
+// Register 注册自定义内容类型，Decode 遇到该类型时使用 factory 创建的值解码
+// 可以覆盖内置类型；factory 应当返回指针，例如 func() payload.Content { return &MyCard{} }
+func Register(t ContentType, factory func() Content) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[t] = factory
+}
+
+func init() {
+	Register(TypeText, func() Content { return &Text{} })
+	Register(TypeImage, func() Content { return &Image{} })
+	Register(TypeGIF, func() Content { return &GIF{} })
+	Register(TypeVoice, func() Content { return &Voice{} })
+	Register(TypeVideo, func() Content { return &Video{} })
+	Register(TypeLocation, func() Content { return &Location{} })
+	Register(TypeCard, func() Content { return &Card{} })
+	Register(TypeFile, func() Content { return &File{} })
+	Register(TypeRevoke, func() Content { return &Revoke{} })
+}
+
+func lookup(t ContentType) (func() Content, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[t]
+	return f, ok
+}
+
+// Encode 把内容编码为 base64 字符串，可以直接赋值给 SendMessageRequest.Payload
+func Encode(c Content) (string, error) {
+	data, err := EncodeJSON(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// EncodeJSON 把内容编码为带 type 字段的 JSON
+func EncodeJSON(c Content) ([]byte, error) {
+	if c == nil {
+		return nil, errors.New("payload: nil content")
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("payload: encode type %d: %w", c.ContentType(), err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("payload: content type %d must encode to a JSON object: %w", c.ContentType(), err)
+	}
+	if fields == nil {
+		fields = map[string]json.RawMessage{}
+	}
+	fields["type"] = json.RawMessage(fmt.Sprint(int(c.ContentType())))
+	return json.Marshal(fields)
+}
+
+// Decode 解码 base64 编码的 Payload
+// 为了兼容，未经 base64 编码的 JSON 对象也可以直接解码
+func Decode(p string) (Content, error) {
+	data, err := base64.StdEncoding.DecodeString(p)
+	if err != nil {
+		trimmed := bytes.TrimSpace([]byte(p))
+		if len(trimmed) == 0 || trimmed[0] != '{' {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		data = trimmed
+	}
+	return DecodeJSON(data)
+}
+
+// DecodeJSON 按 type 字段把内容 JSON 解码为对应的类型
+func DecodeJSON(data []byte) (Content, error) {
+	var head struct {
+		Type ContentType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	var c Content
+	if factory, ok := lookup(head.Type); ok {
+		c = factory()
+	} else if head.Type.IsSystem() {
+		c = &System{}
+	} else {
+		var g Generic
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		return g, nil
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("payload: decode type %d: %w", head.Type, err)
+	}
+	return c, nil
+}
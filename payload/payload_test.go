@@ -0,0 +1,148 @@
+package payload_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/payload"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []payload.Content{
+		&payload.Text{
+			Content: "hi @u2",
+			Mention: &payload.Mention{UIDs: []string{"u2"}},
+			Reply:   &payload.Reply{MessageID: "m1", MessageSeq: 3, FromUID: "u2", Payload: json.RawMessage(`{"type":1,"content":"hello"}`)},
+		},
+		&payload.Image{URL: "https://cdn/a.png", Width: 640, Height: 480},
+		&payload.GIF{URL: "https://cdn/a.gif", Width: 120, Height: 120},
+		&payload.Voice{URL: "https://cdn/a.amr", Duration: 12, Waveform: "AAEC"},
+		&payload.Video{URL: "https://cdn/a.mp4", Cover: "https://cdn/a.jpg", Size: 1 << 20, Width: 1280, Height: 720, Second: 30},
+		&payload.Location{Title: "office", Address: "street 1", Latitude: 31.23, Longitude: 121.47, Img: "https://cdn/map.png"},
+		&payload.Card{UID: "u3", Name: "Bob", Avatar: "https://cdn/bob.png", Vercode: "v1"},
+		&payload.File{URL: "https://cdn/a.pdf", Name: "a.pdf", Size: 2048},
+		&payload.Revoke{MessageID: "m9", ClientMsgNo: "c9"},
+		&payload.System{Type: 1001, Content: "{0} joined", Extra: []any{map[string]any{"uid": "u4", "name": "Carol"}}},
+	}
+	for _, c := range tests {
+		t.Run(reflect.TypeOf(c).Elem().Name(), func(t *testing.T) {
+			p, err := payload.Encode(c)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(p)
+			if err != nil {
+				t.Fatalf("Encode did not produce base64: %v", err)
+			}
+			var head struct {
+				Type payload.ContentType `json:"type"`
+			}
+			if err := json.Unmarshal(raw, &head); err != nil || head.Type != c.ContentType() {
+				t.Errorf("encoded type = %d (err %v), want %d", head.Type, err, c.ContentType())
+			}
+
+			got, err := payload.Decode(p)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, c) {
+				t.Errorf("Decode = %#v, want %#v", got, c)
+			}
+		})
+	}
+}
+
+func TestDecodePlainJSON(t *testing.T) {
+	got, err := payload.Decode(` {"type":1,"content":"hi"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := got.(*payload.Text); !ok || text.Content != "hi" {
+		t.Errorf("Decode = %#v, want *Text hi", got)
+	}
+
+	if _, err := payload.Decode("not a payload"); !errors.Is(err, payload.ErrInvalidPayload) {
+		t.Errorf("err = %v, want ErrInvalidPayload", err)
+	}
+}
+
+func TestDecodeUnknownTypeAsGeneric(t *testing.T) {
+	got, err := payload.DecodeJSON([]byte(`{"type":99,"content":"x","count":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, ok := got.(payload.Generic)
+	if !ok {
+		t.Fatalf("Decode = %T, want Generic", got)
+	}
+	if g.ContentType() != 99 || g["content"] != "x" || g["count"] != float64(2) {
+		t.Errorf("Generic = %v", g)
+	}
+
+	// 未注册的系统消息解码为 *System
+	got, err = payload.DecodeJSON([]byte(`{"type":1500,"content":"{0} left","extra":["u1"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := got.(*payload.System); !ok || s.Type != 1500 || s.Content != "{0} left" {
+		t.Errorf("Decode = %#v, want *System 1500", got)
+	}
+}
+
+// sticker 自定义内容类型
+type sticker struct {
+	Pack string `json:"pack"`
+	ID   int    `json:"id"`
+}
+
+const typeSticker payload.ContentType = 101
+
+func (*sticker) ContentType() payload.ContentType { return typeSticker }
+
+func TestRegisterCustomType(t *testing.T) {
+	payload.Register(typeSticker, func() payload.Content { return &sticker{} })
+
+	p, err := payload.Encode(&sticker{Pack: "cats", ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := payload.Decode(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := got.(*sticker); !ok || *s != (sticker{Pack: "cats", ID: 7}) {
+		t.Errorf("Decode = %#v, want *sticker", got)
+	}
+}
+
+func TestSendMessageRequestSetContent(t *testing.T) {
+	req := &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "u2", ChannelType: wukong.ChannelTypePerson}
+	if err := req.SetContent(&payload.Text{Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 发出的 Payload 原样出现在收到的消息中
+	for _, c := range []interface {
+		Content() (payload.Content, error)
+	}{
+		&wukong.Message{Payload: req.Payload},
+		&wukong.ConversationRecentMessage{Payload: req.Payload},
+	} {
+		got, err := c.Content()
+		if err != nil {
+			t.Fatalf("%T.Content: %v", c, err)
+		}
+		if text, ok := got.(*payload.Text); !ok || text.Content != "hi" {
+			t.Errorf("%T.Content = %#v, want *Text hi", c, got)
+		}
+	}
+
+	if err := req.SetContent(nil); err == nil {
+		t.Error("SetContent(nil) succeeded")
+	}
+}
@@ -0,0 +1,178 @@
+// Package promwukong 提供基于 Prometheus 的 WuKongIM Go SDK 指标收集器
+//
+//	collector := promwukong.NewCollector(promwukong.Opts{})
+//	registry.MustRegister(collector)
+//
+//	cli := wukong.NewClient(wukong.Config{
+//		BaseURL: "http://localhost:5001",
+//		Metrics: collector,
+//	})
+package promwukong
+
+import (
+	"strconv"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Opts 配置指标收集器
+type Opts struct {
+	// Namespace 指标名前缀，默认 "wukongim"
+	Namespace string
+	// Subsystem 指标名的子系统部分，默认 "client"
+	Subsystem string
+	// ConstLabels 附加在所有指标上的固定标签
+	ConstLabels prometheus.Labels
+	// Buckets 耗时直方图的桶，默认 prometheus.DefBuckets
+	Buckets []float64
+}
+
+// Collector 实现了 prometheus.Collector 与 wukong.MetricsRecorder
+// 把它赋值给 Config.Metrics 后，Client 会自动上报调用数据
+type Collector struct {
+	requests *prometheus.CounterVec
+	inFlight *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+
+	circuitState       *prometheus.GaugeVec
+	circuitTransitions *prometheus.CounterVec
+	circuitRejections  *prometheus.CounterVec
+}
+
+var _ wukong.MetricsRecorder = (*Collector)(nil)
+var _ wukong.CircuitMetricsRecorder = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector 创建指标收集器，需要调用方自行注册到 Registry
+func NewCollector(opts Opts) *Collector {
+	if opts.Namespace == "" {
+		opts.Namespace = "wukongim"
+	}
+	if opts.Subsystem == "" {
+		opts.Subsystem = "client"
+	}
+	if len(opts.Buckets) == 0 {
+		opts.Buckets = prometheus.DefBuckets
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "requests_total",
+			Help:        "Total number of WuKongIM API calls.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "in_flight_requests",
+			Help:        "Number of WuKongIM API calls currently in flight.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of WuKongIM API calls, including retries.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     opts.Buckets,
+		}, []string{"operation", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "errors_total",
+			Help:        "Total number of failed WuKongIM API calls.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "code", "api_status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "retries_total",
+			Help:        "Total number of retried WuKongIM API attempts.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"operation", "code"}),
+		circuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "circuit_state",
+			Help:        "Current circuit breaker state (0=closed, 1=open, 2=half-open).",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"endpoint", "operation"}),
+		circuitTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "circuit_transitions_total",
+			Help:        "Total number of circuit breaker state transitions.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"endpoint", "operation", "state"}),
+		circuitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "circuit_rejections_total",
+			Help:        "Total number of calls rejected by an open circuit breaker.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"endpoint", "operation"}),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.retries.Describe(ch)
+	c.circuitState.Describe(ch)
+	c.circuitTransitions.Describe(ch)
+	c.circuitRejections.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.retries.Collect(ch)
+	c.circuitState.Collect(ch)
+	c.circuitTransitions.Collect(ch)
+	c.circuitRejections.Collect(ch)
+}
+
+// OperationStarted 实现 wukong.MetricsRecorder
+func (c *Collector) OperationStarted(name string) {
+	c.inFlight.WithLabelValues(name).Inc()
+}
+
+// OperationFinished 实现 wukong.MetricsRecorder
+func (c *Collector) OperationFinished(name string, statusCode, apiStatus int, elapsed time.Duration, err error) {
+	code := strconv.Itoa(statusCode)
+
+	c.inFlight.WithLabelValues(name).Dec()
+	c.requests.WithLabelValues(name, code).Inc()
+	c.duration.WithLabelValues(name, code).Observe(elapsed.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(name, code, strconv.Itoa(apiStatus)).Inc()
+	}
+}
+
+// RetryAttempted 实现 wukong.MetricsRecorder
+func (c *Collector) RetryAttempted(name string, statusCode int) {
+	c.retries.WithLabelValues(name, strconv.Itoa(statusCode)).Inc()
+}
+
+// CircuitStateChanged 实现 wukong.CircuitMetricsRecorder
+func (c *Collector) CircuitStateChanged(change wukong.CircuitStateChange) {
+	c.circuitState.WithLabelValues(change.Endpoint, change.Operation).Set(float64(change.To))
+	c.circuitTransitions.WithLabelValues(change.Endpoint, change.Operation, change.To.String()).Inc()
+}
+
+// CircuitRejected 实现 wukong.CircuitMetricsRecorder
+func (c *Collector) CircuitRejected(endpoint, operation string) {
+	c.circuitRejections.WithLabelValues(endpoint, operation).Inc()
+}
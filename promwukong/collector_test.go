@@ -0,0 +1,95 @@
+package promwukong_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/promwukong"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectorRecordsCalls(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+
+	collector := promwukong.NewCollector(promwukong.Opts{ConstLabels: prometheus.Labels{"cluster": "test"}})
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	cli := srv.Client(wukong.Config{
+		Metrics: collector,
+		Retry:   &wukong.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	ctx := context.Background()
+
+	srv.InjectFault(wukongtest.Fault{Path: "/channel/delete", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := cli.Channel.Delete(ctx, &wukong.DeleteChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	// 缺少 channel_id，假服务端返回 400
+	if _, err := cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="}); err == nil {
+		t.Fatal("SendMessage: expected an error")
+	}
+
+	want := `
+# HELP wukongim_client_errors_total Total number of failed WuKongIM API calls.
+# TYPE wukongim_client_errors_total counter
+wukongim_client_errors_total{api_status="400",cluster="test",code="400",operation="message.SendMessage"} 1
+# HELP wukongim_client_in_flight_requests Number of WuKongIM API calls currently in flight.
+# TYPE wukongim_client_in_flight_requests gauge
+wukongim_client_in_flight_requests{cluster="test",operation="channel.Delete"} 0
+wukongim_client_in_flight_requests{cluster="test",operation="message.SendMessage"} 0
+# HELP wukongim_client_requests_total Total number of WuKongIM API calls.
+# TYPE wukongim_client_requests_total counter
+wukongim_client_requests_total{cluster="test",code="200",operation="channel.Delete"} 1
+wukongim_client_requests_total{cluster="test",code="400",operation="message.SendMessage"} 1
+# HELP wukongim_client_retries_total Total number of retried WuKongIM API attempts.
+# TYPE wukongim_client_retries_total counter
+wukongim_client_retries_total{cluster="test",code="503",operation="channel.Delete"} 1
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"wukongim_client_errors_total",
+		"wukongim_client_in_flight_requests",
+		"wukongim_client_requests_total",
+		"wukongim_client_retries_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(collector, "wukongim_client_request_duration_seconds"); n != 2 {
+		t.Errorf("duration series = %d, want 2", n)
+	}
+}
+
+func TestCollectorRecordsCircuitEvents(t *testing.T) {
+	collector := promwukong.NewCollector(promwukong.Opts{Namespace: "im", Subsystem: "sdk"})
+	collector.CircuitStateChanged(wukong.CircuitStateChange{Endpoint: "http://node1", From: wukong.CircuitClosed, To: wukong.CircuitOpen})
+	collector.CircuitRejected("http://node1", "")
+	collector.CircuitRejected("http://node1", "")
+
+	want := `
+# HELP im_sdk_circuit_rejections_total Total number of calls rejected by an open circuit breaker.
+# TYPE im_sdk_circuit_rejections_total counter
+im_sdk_circuit_rejections_total{endpoint="http://node1",operation=""} 2
+# HELP im_sdk_circuit_state Current circuit breaker state (0=closed, 1=open, 2=half-open).
+# TYPE im_sdk_circuit_state gauge
+im_sdk_circuit_state{endpoint="http://node1",operation=""} 1
+# HELP im_sdk_circuit_transitions_total Total number of circuit breaker state transitions.
+# TYPE im_sdk_circuit_transitions_total counter
+im_sdk_circuit_transitions_total{endpoint="http://node1",operation="",state="open"} 1
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(want),
+		"im_sdk_circuit_rejections_total",
+		"im_sdk_circuit_state",
+		"im_sdk_circuit_transitions_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
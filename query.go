@@ -0,0 +1,27 @@
+package wukong_go_sdk
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// 查询参数统一通过 url.Values 构造并交给 Client.do，由 resty 负责转义，
+// 不要把参数直接拼接到 path 中，否则包含 &、#、空格或中文的 ID 会破坏 URL
+
+// channelQuery 返回频道类接口通用的查询参数：channel_id 与 channel_type
+func channelQuery(channelID string, channelType ChannelType) url.Values {
+	q := url.Values{}
+	q.Set("channel_id", channelID)
+	q.Set("channel_type", strconv.Itoa(int(channelType)))
+	return q
+}
+
+// intranetQuery 返回路由接口的 intranet 查询参数，取值不合法时返回 nil
+func intranetQuery(intranet IntranetType) url.Values {
+	if intranet != IntranetTypeExternal && intranet != IntranetTypeInternal {
+		return nil
+	}
+	q := url.Values{}
+	q.Set("intranet", strconv.Itoa(int(intranet)))
+	return q
+}
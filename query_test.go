@@ -0,0 +1,86 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// hostileIDs 包含需要转义的字符，直接拼接到 URL 中会截断或注入参数
+var hostileIDs = []string{
+	"a/b",
+	"a?channel_type=1",
+	"a#frag",
+	"100%",
+	"a&channel_type=1",
+	"群组 一",
+	"🙂 x+y",
+}
+
+// queryRecorder 返回记录最后一次请求 path 与查询参数的测试服务
+func queryRecorder(t *testing.T, body string) (*Client, *url.URL) {
+	t.Helper()
+	last := &url.URL{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r.URL
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(Config{BaseURL: srv.URL}), last
+}
+
+func TestQueryEscapesHostileIDs(t *testing.T) {
+	ctx := context.Background()
+	for _, id := range hostileIDs {
+		t.Run(id, func(t *testing.T) {
+			cli, last := queryRecorder(t, "[]")
+			if _, err := cli.Channel.GetWhitelist(ctx, &GetWhitelistRequest{ChannelID: id, ChannelType: ChannelTypeGroup}); err != nil {
+				t.Fatal(err)
+			}
+			if last.Path != "/channel/whitelist" {
+				t.Errorf("path = %q, want /channel/whitelist", last.Path)
+			}
+			q := last.Query()
+			if got := q["channel_id"]; len(got) != 1 || got[0] != id {
+				t.Errorf("channel_id = %q, want [%q]", got, id)
+			}
+			if got := q["channel_type"]; len(got) != 1 || got[0] != "2" {
+				t.Errorf("channel_type = %q, want [2]", got)
+			}
+			if last.Fragment != "" {
+				t.Errorf("fragment = %q, want none", last.Fragment)
+			}
+
+			cli, last = queryRecorder(t, `{"message_seq":1}`)
+			if _, err := cli.Message.GetMaxMessageSeq(ctx, &MaxMessageSeqRequest{LoginUID: id, ChannelID: id, ChannelType: ChannelTypePerson}); err != nil {
+				t.Fatal(err)
+			}
+			q = last.Query()
+			if last.Path != "/channel/max_message_seq" || q.Get("channel_id") != id || q.Get("login_uid") != id || q.Get("channel_type") != "1" || len(q) != 3 {
+				t.Errorf("GetMaxMessageSeq sent %s?%s", last.Path, last.RawQuery)
+			}
+		})
+	}
+}
+
+func TestQueryOptionalParams(t *testing.T) {
+	ctx := context.Background()
+
+	cli, last := queryRecorder(t, "{}")
+	if _, err := cli.Route.GetIMAddress(ctx, &RouteAddressRequest{Intranet: IntranetTypeInternal}); err != nil {
+		t.Fatal(err)
+	}
+	if last.Path != "/route" || last.RawQuery != "intranet=1" {
+		t.Errorf("GetIMAddress sent %s?%s, want /route?intranet=1", last.Path, last.RawQuery)
+	}
+
+	if _, err := cli.Route.GetIMAddress(ctx, &RouteAddressRequest{Intranet: IntranetType(7)}); err != nil {
+		t.Fatal(err)
+	}
+	if last.RawQuery != "" {
+		t.Errorf("invalid intranet sent query %q, want none", last.RawQuery)
+	}
+}
@@ -0,0 +1,190 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit 令牌桶限流参数
+type RateLimit struct {
+	// Rate 每秒允许的请求数，<= 0 表示不限制
+	Rate float64
+	// Burst 令牌桶容量，默认为 Rate 向上取整（至少为 1）
+	Burst int
+}
+
+// RateLimitConfig 客户端限流配置，通过 Config.RateLimit 开启
+// 每次请求（包括重试）都需要先后从全局令牌桶和所属分组的令牌桶取得令牌
+type RateLimitConfig struct {
+	// Global 全局限流
+	Global RateLimit
+	// Groups 按分组限流，key 可以是操作名（例如 "message.SendMessage"），
+	// 也可以是 GroupOf 返回的分组名，操作名优先匹配
+	Groups map[string]RateLimit
+	// GroupOf 返回操作所属的分组，默认取服务名，例如 "message"、"channel"
+	GroupOf func(operation string) string
+
+	// MaxInFlight 同时进行中的请求数上限，<= 0 表示不限制
+	MaxInFlight int
+}
+
+// tokenBucket 简单的令牌桶实现，支持根据服务端 Retry-After 暂停发放
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.Rate))
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait 阻塞直到取得一个令牌或 ctx 结束
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+
+		var d time.Duration
+		if now.Before(b.pausedUntil) {
+			d = b.pausedUntil.Sub(now)
+		} else {
+			b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+			b.last = now
+			if b.tokens >= 1 {
+				b.tokens--
+				b.mu.Unlock()
+				return nil
+			}
+			d = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		}
+		b.mu.Unlock()
+
+		if err := sleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// pause 在 until 之前暂停发放令牌，并清空已有令牌
+func (b *tokenBucket) pause(until time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+		b.tokens = 0
+		b.last = until
+	}
+}
+
+// limiter 组合全局令牌桶、分组令牌桶与并发信号量
+type limiter struct {
+	cfg    *RateLimitConfig
+	global *tokenBucket
+	groups map[string]*tokenBucket
+	sem    chan struct{}
+}
+
+func newLimiter(cfg *RateLimitConfig) *limiter {
+	l := &limiter{
+		cfg:    cfg,
+		global: newTokenBucket(cfg.Global),
+		groups: make(map[string]*tokenBucket, len(cfg.Groups)),
+	}
+	for name, limit := range cfg.Groups {
+		if b := newTokenBucket(limit); b != nil {
+			l.groups[name] = b
+		}
+	}
+	if cfg.MaxInFlight > 0 {
+		l.sem = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return l
+}
+
+// group 返回操作对应的分组令牌桶，操作名优先于分组名
+func (l *limiter) group(operation string) *tokenBucket {
+	if b, ok := l.groups[operation]; ok {
+		return b
+	}
+
+	var name string
+	if l.cfg.GroupOf != nil {
+		name = l.cfg.GroupOf(operation)
+	} else {
+		name = serviceOf(operation)
+	}
+	return l.groups[name]
+}
+
+// acquire 等待限流与并发名额，返回的 release 必须在请求结束后调用
+func (l *limiter) acquire(ctx context.Context, operation string) (release func(), err error) {
+	if err := l.global.wait(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.group(operation).wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if l.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return func() { <-l.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// throttle 根据服务端 429 的 Retry-After 暂停全局与分组令牌桶
+func (l *limiter) throttle(operation string, until time.Time) {
+	l.global.pause(until)
+	l.group(operation).pause(until)
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数与 HTTP 日期两种格式
+func parseRetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,247 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// acquireWithin 在 d 内取得名额时返回 true
+func acquireWithin(t *testing.T, l *limiter, operation string, d time.Duration) bool {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	release, err := l.acquire(ctx, operation)
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("acquire %s: %v", operation, err)
+		}
+		return false
+	}
+	release()
+	return true
+}
+
+func TestTokenBucketBurstAndRefill(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 50, Burst: 2})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := b.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d > 10*time.Millisecond {
+		t.Errorf("burst took %v, want immediate", d)
+	}
+
+	// 令牌耗尽后按 50/s 补充，约 20ms 一个
+	start = time.Now()
+	if err := b.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("third token after %v, want to wait for a refill", d)
+	}
+}
+
+func TestTokenBucketDefaults(t *testing.T) {
+	if b := newTokenBucket(RateLimit{}); b != nil {
+		t.Errorf("zero rate bucket = %+v, want nil", b)
+	}
+	if err := (*tokenBucket)(nil).wait(context.Background()); err != nil {
+		t.Errorf("nil bucket wait: %v", err)
+	}
+
+	tests := []struct {
+		limit RateLimit
+		burst float64
+	}{
+		{RateLimit{Rate: 0.5}, 1},
+		{RateLimit{Rate: 2.5}, 3},
+		{RateLimit{Rate: 2.5, Burst: 10}, 10},
+	}
+	for _, tt := range tests {
+		if b := newTokenBucket(tt.limit); b.burst != tt.burst || b.tokens != tt.burst {
+			t.Errorf("newTokenBucket(%+v) burst = %v tokens = %v, want %v", tt.limit, b.burst, b.tokens, tt.burst)
+		}
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	l := newLimiter(&RateLimitConfig{Global: RateLimit{Rate: 0.1, Burst: 1}})
+	if !acquireWithin(t, l, "message.SendMessage", time.Second) {
+		t.Fatal("first request was limited")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := l.acquire(ctx, "message.SendMessage"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want canceled", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("acquire returned %v after cancel", d)
+	}
+}
+
+func TestLimiterGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RateLimitConfig
+		limited string
+		free    string
+	}{
+		{
+			name:    "by service",
+			cfg:     RateLimitConfig{Groups: map[string]RateLimit{"message": {Rate: 0.1, Burst: 1}}},
+			limited: "message.SendMessage",
+			free:    "channel.Create",
+		},
+		{
+			name: "operation name wins over group",
+			cfg: RateLimitConfig{Groups: map[string]RateLimit{
+				"message.SendMessage": {Rate: 0.1, Burst: 1},
+				"message":             {Rate: 1000},
+			}},
+			limited: "message.SendMessage",
+			free:    "message.Sync",
+		},
+		{
+			name: "custom GroupOf",
+			cfg: RateLimitConfig{
+				Groups: map[string]RateLimit{"send": {Rate: 0.1, Burst: 1}},
+				GroupOf: func(operation string) string {
+					if operation == "message.SendMessage" {
+						return "send"
+					}
+					return serviceOf(operation)
+				},
+			},
+			limited: "message.SendMessage",
+			free:    "message.Sync",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(&tt.cfg)
+			if !acquireWithin(t, l, tt.limited, time.Second) {
+				t.Fatal("first request was limited")
+			}
+			if acquireWithin(t, l, tt.limited, 20*time.Millisecond) {
+				t.Errorf("%s was not limited", tt.limited)
+			}
+			for i := 0; i < 3; i++ {
+				if !acquireWithin(t, l, tt.free, 20*time.Millisecond) {
+					t.Fatalf("%s was limited by another group", tt.free)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterMaxInFlight(t *testing.T) {
+	l := newLimiter(&RateLimitConfig{MaxInFlight: 2})
+	ctx := context.Background()
+
+	r1, err := l.acquire(ctx, "message.SendMessage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := l.acquire(ctx, "channel.Create")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acquireWithin(t, l, "user.UpdateToken", 20*time.Millisecond) {
+		t.Fatal("third in-flight request was admitted")
+	}
+
+	r1()
+	if !acquireWithin(t, l, "user.UpdateToken", 20*time.Millisecond) {
+		t.Error("request was not admitted after release")
+	}
+	r2()
+}
+
+func TestLimiterThrottle(t *testing.T) {
+	l := newLimiter(&RateLimitConfig{
+		Global: RateLimit{Rate: 1000},
+		Groups: map[string]RateLimit{"message": {Rate: 1000}},
+	})
+	l.throttle("message.SendMessage", time.Now().Add(50*time.Millisecond))
+
+	if acquireWithin(t, l, "channel.Create", 20*time.Millisecond) {
+		t.Error("global bucket was not paused")
+	}
+	start := time.Now()
+	if !acquireWithin(t, l, "message.SendMessage", time.Second) {
+		t.Fatal("request was not admitted after the pause")
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("admitted after %v, want to wait for the pause", d)
+	}
+}
+
+func TestRateLimitedResponsePausesClient(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"msg":"slow down","status":429}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+	}))
+	defer srv.Close()
+
+	cli := NewClient(Config{BaseURL: srv.URL, RateLimit: &RateLimitConfig{Global: RateLimit{Rate: 1000}}})
+	defer cli.Close()
+
+	ctx := context.Background()
+	if _, err := cli.System.Health(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	// Retry-After 期间请求在本地等待，不会发到服务端
+	_, err := cli.System.Health(ctx, WithCallTimeout(50*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded while paused", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{now.Add(-5 * time.Second).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		if got, ok := parseRetryAfter(h, now); got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
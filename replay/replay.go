@@ -0,0 +1,262 @@
+// Package replay 把历史消息重新发送到另一个频道或另一个 WuKongIM 集群，用于合并群组或集群迁移
+//
+// 消息来源可以是 WuKongIM 频道（ChannelSource），也可以是 export 包导出的 JSONL 文件（FileSource），
+// 回放时保留 FromUID 与原始顺序，通过 BatchSendMessage 分批写入目标频道：
+//
+//	r := replay.New(dst.Message, replay.ChannelSource(src.Message, ""),
+//		replay.WithCheckpoint(wukong.NewFileCheckpointStore("replay.checkpoint.json")),
+//		replay.WithRewriteClientMsgNo(),
+//		replay.WithRate(200),
+//	)
+//	summaries, err := r.ReplayAll(ctx, replay.Mapping{
+//		From: wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup},
+//		To:   wukong.ChannelKey{ChannelID: "g2", ChannelType: wukong.ChannelTypeGroup},
+//	})
+package replay
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// defaultBatchSize 每次 BatchSendMessage 发送的消息数
+const defaultBatchSize = 100
+
+// Option 配置 Replayer
+type Option func(*Replayer)
+
+// WithBatchSize 设置每批发送的消息数，默认 100
+func WithBatchSize(n int) Option {
+	return func(r *Replayer) {
+		r.batchSize = n
+	}
+}
+
+// WithRate 限制每秒回放的消息数，<= 0 表示不限制
+func WithRate(perSecond float64) Option {
+	return func(r *Replayer) {
+		r.rate = perSecond
+	}
+}
+
+// WithCheckpoint 使用 store 记录每个源频道已经回放到的 seq，中断后再次回放时从断点之后继续
+// 断点以源频道为 key，同一个源频道回放到多个目标时需要使用不同的 store
+func WithCheckpoint(store wukong.CheckpointStore) Option {
+	return func(r *Replayer) {
+		r.checkpoint = store
+	}
+}
+
+// WithRewriteClientMsgNo 根据源频道、源 seq 与目标频道生成确定的 ClientMsgNo
+// 重复回放同一段消息时 ClientMsgNo 不变，开启重试时批量发送也可以安全重试
+// 默认沿用原消息的 ClientMsgNo，原消息没有 ClientMsgNo 时同样按上述规则生成
+func WithRewriteClientMsgNo() Option {
+	return func(r *Replayer) {
+		r.rewrite = true
+	}
+}
+
+// WithHeader 设置回放消息的 header，默认不显示红点，避免导入历史时产生大量未读
+func WithHeader(header wukong.MessageHeader) Option {
+	return func(r *Replayer) {
+		r.header = header
+	}
+}
+
+// Mapping 一组源频道到目标频道的映射
+type Mapping struct {
+	From wukong.ChannelKey
+	To   wukong.ChannelKey
+}
+
+// Summary 单个频道的回放结果
+type Summary struct {
+	From wukong.ChannelKey
+	To   wukong.ChannelKey
+	// ResumedAfter 本次回放开始前断点中记录的源 seq，0 表示从头开始
+	ResumedAfter int64
+	// FirstSeq / LastSeq 本次回放的源消息 seq 范围，没有回放任何消息时为 0
+	FirstSeq int64
+	LastSeq  int64
+	// Replayed 成功发送的消息数，批次部分失败时只统计第一条失败消息之前的部分
+	Replayed int
+	// Batches 调用 BatchSendMessage 的次数
+	Batches  int
+	Duration time.Duration
+}
+
+// Replayer 从 Source 读取消息并发送到目标客户端
+type Replayer struct {
+	dst        wukong.MessageAPI
+	src        Source
+	batchSize  int
+	rate       float64
+	checkpoint wukong.CheckpointStore
+	rewrite    bool
+	header     wukong.MessageHeader
+}
+
+// New 创建 Replayer，dst 为目标集群的 Client.Message，可以与来源是同一个客户端
+func New(dst wukong.MessageAPI, src Source, opts ...Option) *Replayer {
+	r := &Replayer{dst: dst, src: src}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultBatchSize
+	}
+	return r
+}
+
+// ReplayAll 依次回放多个频道，遇到错误时停止，返回已经处理的频道（包括出错频道）的结果
+func (r *Replayer) ReplayAll(ctx context.Context, mappings ...Mapping) ([]Summary, error) {
+	out := make([]Summary, 0, len(mappings))
+	for _, m := range mappings {
+		s, err := r.Replay(ctx, m.From, m.To)
+		out = append(out, s)
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// Replay 把源频道 from 中断点之后的消息按原始顺序发送到目标频道 to
+// 每批发送成功后保存断点，失败时返回已经回放部分的统计
+func (r *Replayer) Replay(ctx context.Context, from, to wukong.ChannelKey) (s Summary, err error) {
+	started := time.Now()
+	s = Summary{From: from, To: to}
+	defer func() { s.Duration = time.Since(started) }()
+
+	if r.checkpoint != nil {
+		seq, err := r.checkpoint.Load(ctx, from)
+		if err != nil {
+			return s, fmt.Errorf("replay: channel %s: load checkpoint: %w", from, err)
+		}
+		s.ResumedAfter = seq
+	}
+
+	p := newPacer(r.rate)
+	batch := make([]wukong.SendMessageRequest, 0, r.batchSize)
+	// seqs 当前批次每条消息的源 seq，lastSeq 已经读取到的最大源 seq
+	seqs := make([]int64, 0, r.batchSize)
+	var lastSeq int64
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := p.wait(ctx, len(batch)); err != nil {
+			return err
+		}
+		// 通过 SendMany 按 ClientMsgNo 核对每条消息的响应，服务端只接受了部分消息时也能发现
+		res, err := wukong.SendMany(ctx, r.dst, &wukong.SendManyRequest{Messages: batch, ChunkSize: len(batch), Concurrency: 1})
+		s.Batches++
+		sent := len(batch)
+		if err != nil {
+			sent = slices.IndexFunc(res.Items, func(it wukong.BatchResultItem) bool { return it.Err != nil })
+		}
+		// 断点只推进到第一条失败消息之前，之后已经成功的消息再次回放时由 ClientMsgNo 去重
+		if sent > 0 {
+			if r.checkpoint != nil {
+				if cpErr := r.checkpoint.Save(ctx, from, seqs[sent-1]); cpErr != nil {
+					return fmt.Errorf("save checkpoint: %w", cpErr)
+				}
+			}
+			if s.FirstSeq == 0 {
+				s.FirstSeq = seqs[0]
+			}
+			s.LastSeq = seqs[sent-1]
+			s.Replayed += sent
+		}
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+		seqs = seqs[:0]
+		return nil
+	}
+
+	for m, err := range r.src.Messages(ctx, from, s.ResumedAfter) {
+		if err != nil {
+			return s, fmt.Errorf("replay: channel %s: read: %w", from, err)
+		}
+		if m.MessageSeq <= s.ResumedAfter || m.MessageSeq <= lastSeq {
+			continue
+		}
+
+		batch = append(batch, r.request(m, from, to))
+		seqs = append(seqs, m.MessageSeq)
+		lastSeq = m.MessageSeq
+		if len(batch) >= r.batchSize {
+			if err := flush(); err != nil {
+				return s, fmt.Errorf("replay: channel %s -> %s: %w", from, to, err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return s, fmt.Errorf("replay: channel %s -> %s: %w", from, to, err)
+	}
+	return s, nil
+}
+
+// request 把源消息转换为发送到目标频道的请求
+func (r *Replayer) request(m wukong.Message, from, to wukong.ChannelKey) wukong.SendMessageRequest {
+	clientMsgNo := m.ClientMsgNo
+	if r.rewrite || clientMsgNo == "" {
+		clientMsgNo = wukong.StableClientMsgNo(from.String(), strconv.FormatInt(m.MessageSeq, 10), to.String())
+	}
+	header := r.header
+	return wukong.SendMessageRequest{
+		Header:      &header,
+		ClientMsgNo: clientMsgNo,
+		FromUID:     m.FromUID,
+		ChannelID:   to.ChannelID,
+		ChannelType: to.ChannelType,
+		Payload:     m.Payload,
+	}
+}
+
+// pacer 按固定速率放行消息，一批消息占用与条数成正比的时间
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newPacer(rate float64) *pacer {
+	if rate <= 0 {
+		return &pacer{}
+	}
+	return &pacer{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// wait 等待直到可以发送 n 条消息
+func (p *pacer) wait(ctx context.Context, n int) error {
+	if p.interval <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if p.next.Before(now) {
+		p.next = now
+	}
+	d := p.next.Sub(now)
+	p.next = p.next.Add(time.Duration(n) * p.interval)
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
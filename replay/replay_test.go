@@ -0,0 +1,121 @@
+package replay_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/replay"
+	"github.com/linabellbiu/wukong-go-sdk/wukongmock"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+)
+
+func TestReplayPersonChannel(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	if _, err := cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1", "u2"}}); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "u2", ChannelType: wukong.ChannelTypePerson, Payload: "aGk="}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := replay.New(cli.Message, replay.ChannelSource(cli.Message, "u2"))
+	s, err := r.Replay(ctx,
+		wukong.ChannelKey{ChannelID: "u1", ChannelType: wukong.ChannelTypePerson},
+		wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Replayed != 3 || s.LastSeq != 3 {
+		t.Errorf("summary = %+v, want 3 replayed up to seq 3", s)
+	}
+	if got := len(srv.Messages("g1", wukong.ChannelTypeGroup)); got != 3 {
+		t.Errorf("g1 has %d messages, want 3", got)
+	}
+}
+
+// sliceSource 按顺序产出固定的消息
+type sliceSource []wukong.Message
+
+func (s sliceSource) Messages(_ context.Context, _ wukong.ChannelKey, afterSeq int64) iter.Seq2[wukong.Message, error] {
+	return func(yield func(wukong.Message, error) bool) {
+		for _, m := range s {
+			if m.MessageSeq > afterSeq && !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+func sourceMessages(n int) sliceSource {
+	var out sliceSource
+	for seq := int64(1); seq <= int64(n); seq++ {
+		out = append(out, wukong.Message{MessageSeq: seq, FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="})
+	}
+	return out
+}
+
+func TestReplayDerivesMissingClientMsgNo(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	if _, err := cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g2", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1"}}); err != nil {
+		t.Fatal(err)
+	}
+	from := wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}
+	to := wukong.ChannelKey{ChannelID: "g2", ChannelType: wukong.ChannelTypeGroup}
+
+	// 没有断点时重复回放，相同的 ClientMsgNo 被服务端去重
+	for range 2 {
+		if _, err := replay.New(cli.Message, sourceMessages(3)).Replay(ctx, from, to); err != nil {
+			t.Fatal(err)
+		}
+	}
+	msgs := srv.Messages("g2", wukong.ChannelTypeGroup)
+	if len(msgs) != 3 {
+		t.Fatalf("g2 has %d messages after replaying twice, want 3", len(msgs))
+	}
+	for _, m := range msgs {
+		if m.ClientMsgNo == "" {
+			t.Errorf("seq %d was sent without a ClientMsgNo", m.MessageSeq)
+		}
+	}
+}
+
+func TestReplayPartialBatch(t *testing.T) {
+	ctx := context.Background()
+	api := wukongmock.NewAPI()
+	// 服务端只接受了每批的前两条消息
+	api.Message.BatchSendMessageFunc = func(_ context.Context, req *wukong.BatchSendMessageRequest, _ ...wukong.CallOption) ([]wukong.BatchSendMessageResponseItem, error) {
+		var out []wukong.BatchSendMessageResponseItem
+		for i, m := range req.Messages[:min(2, len(req.Messages))] {
+			out = append(out, wukong.BatchSendMessageResponseItem{MessageID: int64(i + 1), MessageSeq: int64(i + 1), ClientMsgNo: m.ClientMsgNo})
+		}
+		return out, nil
+	}
+
+	store := wukong.NewMemoryCheckpointStore()
+	from := wukong.ChannelKey{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}
+	r := replay.New(api.Message, sourceMessages(5), replay.WithBatchSize(4), replay.WithCheckpoint(store))
+	s, err := r.Replay(ctx, from, wukong.ChannelKey{ChannelID: "g2", ChannelType: wukong.ChannelTypeGroup})
+	if !errors.Is(err, wukong.ErrNoBatchResponse) {
+		t.Fatalf("err = %v, want ErrNoBatchResponse", err)
+	}
+	if s.Replayed != 2 || s.LastSeq != 2 {
+		t.Errorf("summary = %+v, want 2 replayed up to seq 2", s)
+	}
+	if seq, _ := store.Load(ctx, from); seq != 2 {
+		t.Errorf("checkpoint = %d, want 2", seq)
+	}
+}
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"context"
+	"iter"
+	"os"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/export"
+)
+
+// Source 待回放消息的来源
+type Source interface {
+	// Messages 按 seq 递增产出 channel 中 seq 大于 afterSeq 的消息
+	Messages(ctx context.Context, channel wukong.ChannelKey, afterSeq int64) iter.Seq2[wukong.Message, error]
+}
+
+// ChannelSource 从 WuKongIM 频道中通过 MessageSync 读取开始回放时已有的历史消息，api 通常为源集群 Client.Message
+// loginUID 读取个人频道时使用，其它频道可以为空
+func ChannelSource(api wukong.MessageAPI, loginUID string) Source {
+	return &channelSource{api: api, loginUID: loginUID}
+}
+
+type channelSource struct {
+	api      wukong.MessageAPI
+	loginUID string
+}
+
+// Messages 只读取到开始时的最大 seq，回放到同一个频道时不会读到自己写入的消息
+func (s *channelSource) Messages(ctx context.Context, channel wukong.ChannelKey, afterSeq int64) iter.Seq2[wukong.Message, error] {
+	return func(yield func(wukong.Message, error) bool) {
+		resp, err := s.api.GetMaxMessageSeq(ctx, &wukong.MaxMessageSeqRequest{LoginUID: s.loginUID, ChannelID: channel.ChannelID, ChannelType: channel.ChannelType})
+		if err != nil {
+			yield(wukong.Message{}, err)
+			return
+		}
+		if resp.MaxMessageSeq <= afterSeq {
+			return
+		}
+
+		hist := wukong.HistoryOptions{
+			LoginUID:  s.loginUID,
+			Direction: wukong.PullModeUp,
+			StartSeq:  afterSeq + 1,
+			EndSeq:    resp.MaxMessageSeq,
+		}
+		for m, err := range wukong.IterateHistory(ctx, s.api, channel, hist) {
+			if !yield(m, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// FileSource 从 export.JSONLWriter 导出的 JSONL 文件读取消息
+// 文件可以包含多个频道，每次回放一个频道都会重新扫描文件，只返回该频道的消息；文件内同一频道的消息需要按 seq 递增
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Messages(ctx context.Context, channel wukong.ChannelKey, afterSeq int64) iter.Seq2[wukong.Message, error] {
+	return func(yield func(wukong.Message, error) bool) {
+		f, err := os.Open(s.path)
+		if err != nil {
+			yield(wukong.Message{}, err)
+			return
+		}
+		defer f.Close()
+
+		for rec, err := range export.ReadJSONL(f) {
+			if err != nil {
+				yield(wukong.Message{}, err)
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(wukong.Message{}, err)
+				return
+			}
+			if rec.ChannelID != channel.ChannelID || rec.ChannelType != channel.ChannelType || rec.MessageSeq <= afterSeq {
+				continue
+			}
+			if !yield(rec.Message, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy 描述 Client.do 在遇到临时性故障时的重试策略
+// 通过 Config.Retry 开启，为 nil 时每个请求只执行一次
+//
+// 重试是幂等感知的：GET 以及频道、用户等管理类接口可以自由重试；
+// 发送消息类接口（SendMessage / BatchSendMessage / Event.Send）
+// 只有在设置了 ClientMsgNo、服务端可以据此去重时才会重试。
+type RetryPolicy struct {
+	// MaxAttempts 包含首次请求在内的最大尝试次数，<= 1 表示不重试
+	MaxAttempts int
+
+	// InitialBackoff 第一次重试前的等待时间，默认 100ms
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时间上限，默认 2s
+	MaxBackoff time.Duration
+	// Multiplier 指数退避的倍数，默认 2
+	Multiplier float64
+	// Jitter 抖动比例，取值 0~1，实际等待时间在 [d*(1-Jitter), d] 之间随机
+	Jitter float64
+
+	// RetryableStatusCodes 需要重试的 HTTP 状态码，为空时使用 502/503/504
+	RetryableStatusCodes []int
+	// RetryableError 判断传输层错误是否可以重试，为空时使用内置的网络错误判断
+	// （连接被拒绝、连接重置、超时、意外 EOF 等）
+	RetryableError func(err error) bool
+
+	// OnAttempt 每次尝试结束后回调，可用于日志或监控
+	OnAttempt func(ctx context.Context, attempt RetryAttempt)
+}
+
+// RetryAttempt 描述一次请求尝试的结果
+type RetryAttempt struct {
+	// Name 操作名，例如 "message.SendMessage"
+	Name   string
+	Method string
+	Path   string
+	// Endpoint 本次尝试使用的节点地址
+	Endpoint string
+
+	// Attempt 从 1 开始的尝试序号
+	Attempt int
+	// StatusCode 服务端返回的 HTTP 状态码，传输层错误时为 0
+	StatusCode int
+	// Err 本次尝试的错误，成功时为 nil
+	Err error
+
+	// WillRetry 是否会继续重试
+	WillRetry bool
+	// Backoff 下一次重试前的等待时间
+	Backoff time.Duration
+}
+
+// DefaultRetryPolicy 返回一个推荐的重试策略：最多 3 次尝试，100ms 起的指数退避
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// RetryError 在开启重试策略后包装最终错误，记录总共尝试的次数
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetry 判断一次尝试的结果是否值得重试
+// statusCode 为 0 表示请求没有拿到响应（传输层错误）
+func (p *RetryPolicy) shouldRetry(statusCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if statusCode == 0 {
+		if p.RetryableError != nil {
+			return p.RetryableError(err)
+		}
+		return isRetryableNetworkError(err)
+	}
+
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	return slices.Contains(codes, statusCode)
+}
+
+// backoff 计算第 attempt 次尝试失败后的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 2 * time.Second
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if d > float64(maxBackoff) {
+		d = float64(maxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		d -= d * jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) observe(ctx context.Context, attempt RetryAttempt) {
+	if p != nil && p.OnAttempt != nil {
+		p.OnAttempt(ctx, attempt)
+	}
+}
+
+// isRetryableNetworkError 内置的传输层错误判断
+// 调用方主动取消的请求不会重试
+func isRetryableNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// idempotent 判断请求失败后是否可以安全地重放
+// 消息发送只有在带上 ClientMsgNo 时，服务端才能去重
+func idempotent(method string, reqBody any) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	switch body := reqBody.(type) {
+	case *SendMessageRequest:
+		return body.ClientMsgNo != ""
+	case []SendMessageRequest:
+		for i := range body {
+			if body[i].ClientMsgNo == "" {
+				return false
+			}
+		}
+		return true
+	case *EventSendRequest:
+		return body.ClientMsgNo != ""
+	}
+	return true
+}
+
+// sleepContext 等待 d，ctx 结束时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
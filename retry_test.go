@@ -0,0 +1,238 @@
+package wukong_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{"defaults first", RetryPolicy{}, 1, 100 * time.Millisecond},
+		{"defaults grow", RetryPolicy{}, 3, 400 * time.Millisecond},
+		{"defaults cap", RetryPolicy{}, 10, 2 * time.Second},
+		{"custom growth", RetryPolicy{InitialBackoff: 10 * time.Millisecond, Multiplier: 3}, 3, 90 * time.Millisecond},
+		{"custom cap", RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}, 4, 50 * time.Millisecond},
+		{"multiplier below 1 falls back to 2", RetryPolicy{InitialBackoff: 10 * time.Millisecond, Multiplier: 0.5}, 2, 20 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.attempt); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		lo, hi time.Duration
+	}{
+		{"partial", 0.2, 80 * time.Millisecond, 100 * time.Millisecond},
+		{"full", 1, 0, 100 * time.Millisecond},
+		{"clamped to 1", 5, 0, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: tt.jitter}
+			for i := 0; i < 1000; i++ {
+				if d := p.backoff(1); d < tt.lo || d > tt.hi {
+					t.Fatalf("backoff = %v, want within [%v, %v]", d, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		status int
+		err    error
+		want   bool
+	}{
+		{"success", RetryPolicy{}, 200, nil, false},
+		{"default 502", RetryPolicy{}, 502, errBoom, true},
+		{"default 503", RetryPolicy{}, 503, errBoom, true},
+		{"default 504", RetryPolicy{}, 504, errBoom, true},
+		{"default 500", RetryPolicy{}, 500, errBoom, false},
+		{"default 429", RetryPolicy{}, 429, errBoom, false},
+		{"custom codes", RetryPolicy{RetryableStatusCodes: []int{429}}, 429, errBoom, true},
+		{"custom codes replace defaults", RetryPolicy{RetryableStatusCodes: []int{429}}, 503, errBoom, false},
+		{"connection refused", RetryPolicy{}, 0, fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"unexpected eof", RetryPolicy{}, 0, io.ErrUnexpectedEOF, true},
+		{"net op error", RetryPolicy{}, 0, &net.OpError{Op: "read", Err: errBoom}, true},
+		{"canceled", RetryPolicy{}, 0, context.Canceled, false},
+		{"unknown transport error", RetryPolicy{}, 0, errBoom, false},
+		{"custom RetryableError", RetryPolicy{RetryableError: func(err error) bool { return errors.Is(err, errBoom) }}, 0, errBoom, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.shouldRetry(tt.status, tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%d, %v) = %v, want %v", tt.status, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   any
+		want   bool
+	}{
+		{"get", http.MethodGet, nil, true},
+		{"send without client_msg_no", http.MethodPost, &SendMessageRequest{}, false},
+		{"send with client_msg_no", http.MethodPost, &SendMessageRequest{ClientMsgNo: "m1"}, true},
+		{"batch all with client_msg_no", http.MethodPost, []SendMessageRequest{{ClientMsgNo: "a"}, {ClientMsgNo: "b"}}, true},
+		{"batch partly without client_msg_no", http.MethodPost, []SendMessageRequest{{ClientMsgNo: "a"}, {}}, false},
+		{"event without client_msg_no", http.MethodPost, &EventSendRequest{}, false},
+		{"event with client_msg_no", http.MethodPost, &EventSendRequest{ClientMsgNo: "e1"}, true},
+		{"management post", http.MethodPost, &OnlineStatusRequest{UIDs: []string{"u1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotent(tt.method, tt.body); got != tt.want {
+				t.Errorf("idempotent(%s, %T) = %v, want %v", tt.method, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+// newFlakyServer 前 failures 次请求返回 503，之后返回成功
+func newFlakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, `{"msg":"unavailable","status":503}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"message_id":1,"message_seq":1,"client_msg_no":"m1"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+type attemptRecorder struct {
+	mu       sync.Mutex
+	attempts []RetryAttempt
+}
+
+func (r *attemptRecorder) policy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		OnAttempt: func(_ context.Context, a RetryAttempt) {
+			r.mu.Lock()
+			r.attempts = append(r.attempts, a)
+			r.mu.Unlock()
+		},
+	}
+}
+
+func TestRetryRecoversAfterFailures(t *testing.T) {
+	srv, hits := newFlakyServer(t, 2)
+	var rec attemptRecorder
+	cli := NewClient(Config{BaseURL: srv.URL, Retry: rec.policy(3)})
+	defer cli.Close()
+
+	if _, err := cli.Message.SendMessage(context.Background(), &SendMessageRequest{ClientMsgNo: "m1"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("server hits = %d, want 3", got)
+	}
+
+	want := []struct {
+		status    int
+		willRetry bool
+	}{{503, true}, {503, true}, {200, false}}
+	if len(rec.attempts) != len(want) {
+		t.Fatalf("OnAttempt called %d times, want %d", len(rec.attempts), len(want))
+	}
+	for i, a := range rec.attempts {
+		if a.Attempt != i+1 || a.StatusCode != want[i].status || a.WillRetry != want[i].willRetry {
+			t.Errorf("attempt %d = %+v, want status %d willRetry %v", i+1, a, want[i].status, want[i].willRetry)
+		}
+		if a.Name != "message.SendMessage" || a.Path != "/message/send" {
+			t.Errorf("attempt %d describes %s %s", i+1, a.Name, a.Path)
+		}
+		if a.WillRetry && a.Backoff != time.Millisecond {
+			t.Errorf("attempt %d backoff = %v, want 1ms", i+1, a.Backoff)
+		}
+	}
+}
+
+func TestRetryErrorReportsAttempts(t *testing.T) {
+	srv, hits := newFlakyServer(t, 10)
+	var rec attemptRecorder
+	cli := NewClient(Config{BaseURL: srv.URL, Retry: rec.policy(3)})
+	defer cli.Close()
+
+	_, err := cli.Message.SendMessage(context.Background(), &SendMessageRequest{ClientMsgNo: "m1"})
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("err = %v, want *RetryError", err)
+	}
+	if retryErr.Attempts != 3 || hits.Load() != 3 {
+		t.Errorf("Attempts = %d, hits = %d, want 3", retryErr.Attempts, hits.Load())
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HttpCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want wrapped 503 APIError", err)
+	}
+	if last := rec.attempts[len(rec.attempts)-1]; last.WillRetry {
+		t.Errorf("last attempt %+v should not retry", last)
+	}
+}
+
+func TestRetrySkipsSendWithoutClientMsgNo(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *SendMessageRequest
+		wantHits int32
+	}{
+		{"without client_msg_no", &SendMessageRequest{}, 1},
+		{"with client_msg_no", &SendMessageRequest{ClientMsgNo: "m1"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := newFlakyServer(t, 10)
+			var rec attemptRecorder
+			cli := NewClient(Config{BaseURL: srv.URL, Retry: rec.policy(3)})
+			defer cli.Close()
+
+			_, err := cli.Message.SendMessage(context.Background(), tt.req)
+			var retryErr *RetryError
+			if !errors.As(err, &retryErr) || retryErr.Attempts != int(tt.wantHits) {
+				t.Fatalf("err = %v, want RetryError after %d attempts", err, tt.wantHits)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package wukong_go_sdk
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// SeqTrackerConfig SeqTracker 配置
+type SeqTrackerConfig struct {
+	// Store 水位存储，默认 NewMemoryCheckpointStore()
+	Store CheckpointStore
+	// LoginUID 跟踪个人频道时使用的登录用户，用于 GetMaxMessageSeq 与补齐消息
+	// 水位按 ChannelKey 保存，不同 LoginUID 跟踪同一个对方 UID 时需要使用不同的 Store
+	LoginUID string
+	// PageSize 补齐缺口时每次拉取的条数，默认 100
+	PageSize int
+	// Backfill 频道没有水位时是否从第一条消息开始补齐
+	// 默认不补齐，以第一次观察到的消息（或 Repair 时的最大序号）作为起点
+	Backfill bool
+	// OnMissing 缺口中的消息在服务端也不存在（例如不存储的消息或已过期）时回调，[start, end] 为无法补齐的序号范围
+	// 这些序号会被跳过，水位照常推进
+	OnMissing func(channel ChannelKey, start, end int64)
+}
+
+// SeqTracker 按频道跟踪已经处理到的消息序号，发现缺口时通过 MessageSync 拉取缺失的消息
+//
+//	tracker := wukong.NewSeqTracker(cli.Message, wukong.SeqTrackerConfig{
+//		Store: wukong.NewFileCheckpointStore("/var/lib/indexer/checkpoint.json"),
+//	})
+//	msgs, err := tracker.Observe(ctx, received...) // 返回补齐后按 seq 排列的消息
+//
+// 同一频道的调用会被串行化，不同频道之间可以并发
+type SeqTracker struct {
+	api MessageAPI
+	cfg SeqTrackerConfig
+
+	mu         sync.Mutex
+	locks      map[ChannelKey]*channelLock
+	watermarks map[ChannelKey]int64
+}
+
+// channelLock 频道锁，refs 为持有或等待该锁的调用数，降为 0 时从 SeqTracker.locks 中删除
+type channelLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewSeqTracker 创建 SeqTracker，api 通常为 Client.Message
+func NewSeqTracker(api MessageAPI, cfg SeqTrackerConfig) *SeqTracker {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryCheckpointStore()
+	}
+	return &SeqTracker{
+		api:        api,
+		cfg:        cfg,
+		locks:      map[ChannelKey]*channelLock{},
+		watermarks: map[ChannelKey]int64{},
+	}
+}
+
+// Observe 处理新观察到的消息：丢弃已经处理过的消息，补齐与水位之间的缺口，并推进水位
+// 返回的消息按频道分组，同一频道内按 seq 递增且不重复
+// 中途失败时返回已经处理的消息以及错误，水位只推进到已经返回的消息
+func (t *SeqTracker) Observe(ctx context.Context, msgs ...Message) ([]Message, error) {
+	var (
+		order  []ChannelKey
+		groups = map[ChannelKey][]Message{}
+	)
+	for _, m := range msgs {
+		key := ChannelKey{ChannelID: m.ChannelID, ChannelType: m.ChannelType}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], m)
+	}
+
+	var out []Message
+	for _, key := range order {
+		emitted, err := t.observe(ctx, key, groups[key])
+		out = append(out, emitted...)
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// Repair 对比 GetMaxMessageSeq 补齐频道水位之后缺失的消息，返回按 seq 递增的消息
+// 适合在服务重启或长时间断线后调用
+func (t *SeqTracker) Repair(ctx context.Context, channel ChannelKey) ([]Message, error) {
+	unlock := t.lock(channel)
+	defer unlock()
+
+	wm, err := t.watermark(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.api.GetMaxMessageSeq(ctx, &MaxMessageSeqRequest{LoginUID: t.cfg.LoginUID, ChannelID: channel.ChannelID, ChannelType: channel.ChannelType})
+	if err != nil {
+		return nil, err
+	}
+	maxSeq := resp.MaxMessageSeq
+	if maxSeq <= wm {
+		return nil, nil
+	}
+	if wm == 0 && !t.cfg.Backfill {
+		return nil, t.save(ctx, channel, maxSeq)
+	}
+
+	filled, err := t.fill(ctx, channel, wm+1, maxSeq)
+	if err != nil {
+		return nil, err
+	}
+	return filled, t.save(ctx, channel, maxSeq)
+}
+
+// Watermark 返回频道当前的水位，即已经处理到的最大消息序号
+func (t *SeqTracker) Watermark(ctx context.Context, channel ChannelKey) (int64, error) {
+	unlock := t.lock(channel)
+	defer unlock()
+	return t.watermark(ctx, channel)
+}
+
+// observe 处理同一频道的消息
+func (t *SeqTracker) observe(ctx context.Context, channel ChannelKey, msgs []Message) ([]Message, error) {
+	unlock := t.lock(channel)
+	defer unlock()
+
+	wm, err := t.watermark(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(msgs, func(a, b Message) int {
+		return cmp.Compare(a.MessageSeq, b.MessageSeq)
+	})
+	if wm == 0 && !t.cfg.Backfill && len(msgs) > 0 {
+		wm = msgs[0].MessageSeq - 1
+	}
+
+	start := wm
+	var out []Message
+	for _, m := range msgs {
+		if m.MessageSeq <= wm {
+			continue
+		}
+		if m.MessageSeq > wm+1 {
+			filled, err := t.fill(ctx, channel, wm+1, m.MessageSeq-1)
+			if err != nil {
+				return out, t.saveAfter(ctx, channel, start, wm, err)
+			}
+			out = append(out, filled...)
+		}
+		out = append(out, m)
+		wm = m.MessageSeq
+	}
+	return out, t.saveAfter(ctx, channel, start, wm, nil)
+}
+
+// fill 拉取 [start, end] 范围内的消息，无法补齐的范围交给 OnMissing
+func (t *SeqTracker) fill(ctx context.Context, channel ChannelKey, start, end int64) ([]Message, error) {
+	hist := HistoryOptions{
+		LoginUID:  t.cfg.LoginUID,
+		Direction: PullModeUp,
+		StartSeq:  start,
+		EndSeq:    end,
+		PageSize:  t.cfg.PageSize,
+	}
+
+	var out []Message
+	next := start
+	for m, err := range IterateHistory(ctx, t.api, channel, hist) {
+		if err != nil {
+			return nil, fmt.Errorf("wukongim: fill channel %s seq %d-%d: %w", channel, start, end, err)
+		}
+		if m.MessageSeq > next {
+			t.missing(channel, next, m.MessageSeq-1)
+		}
+		out = append(out, m)
+		next = m.MessageSeq + 1
+	}
+	if next <= end {
+		t.missing(channel, next, end)
+	}
+	return out, nil
+}
+
+func (t *SeqTracker) missing(channel ChannelKey, start, end int64) {
+	if t.cfg.OnMissing != nil {
+		t.cfg.OnMissing(channel, start, end)
+	}
+}
+
+// lock 获取频道锁，返回解锁函数
+// 锁按引用计数管理，没有调用使用时立即删除，跟踪大量频道时不会无限增长
+func (t *SeqTracker) lock(channel ChannelKey) func() {
+	t.mu.Lock()
+	l, ok := t.locks[channel]
+	if !ok {
+		l = &channelLock{}
+		t.locks[channel] = l
+	}
+	l.refs++
+	t.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		t.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(t.locks, channel)
+		}
+		t.mu.Unlock()
+	}
+}
+
+// watermark 返回缓存的水位，第一次访问时从 Store 读取，调用方需持有频道锁
+func (t *SeqTracker) watermark(ctx context.Context, channel ChannelKey) (int64, error) {
+	t.mu.Lock()
+	wm, ok := t.watermarks[channel]
+	t.mu.Unlock()
+	if ok {
+		return wm, nil
+	}
+
+	wm, err := t.cfg.Store.Load(ctx, channel)
+	if err != nil {
+		return 0, fmt.Errorf("wukongim: load checkpoint for channel %s: %w", channel, err)
+	}
+	t.mu.Lock()
+	t.watermarks[channel] = wm
+	t.mu.Unlock()
+	return wm, nil
+}
+
+// save 保存水位，调用方需持有频道锁
+func (t *SeqTracker) save(ctx context.Context, channel ChannelKey, seq int64) error {
+	if err := t.cfg.Store.Save(ctx, channel, seq); err != nil {
+		return fmt.Errorf("wukongim: save checkpoint for channel %s: %w", channel, err)
+	}
+	t.mu.Lock()
+	t.watermarks[channel] = seq
+	t.mu.Unlock()
+	return nil
+}
+
+// saveAfter 水位有变化时保存，并优先返回处理过程中的错误
+func (t *SeqTracker) saveAfter(ctx context.Context, channel ChannelKey, prev, seq int64, err error) error {
+	if seq != prev {
+		if saveErr := t.save(ctx, channel, seq); err == nil {
+			err = saveErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package wukong_go_sdk_test
+
+import (
+	"context"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+)
+
+func TestSeqTrackerRepairPersonChannel(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	sendPerson(t, cli, "u1", "u2", 3)
+
+	tracker := wukong.NewSeqTracker(cli.Message, wukong.SeqTrackerConfig{LoginUID: "u2", Backfill: true})
+	channel := wukong.ChannelKey{ChannelID: "u1", ChannelType: wukong.ChannelTypePerson}
+	msgs, err := tracker.Repair(ctx, channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("Repair returned %d messages, want 3", len(msgs))
+	}
+	wm, err := tracker.Watermark(ctx, channel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wm != 3 {
+		t.Errorf("watermark = %d, want 3", wm)
+	}
+}
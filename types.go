@@ -1,5 +1,7 @@
 package wukong_go_sdk
 
+import "strconv"
+
 // ChannelType 表示频道类型，例如个人频道、群组频道等
 type ChannelType int
 
@@ -26,6 +28,17 @@ const (
 	ChannelTypeGroupAgent ChannelType = 12
 )
 
+// ChannelKey 唯一标识一个频道，可以作为 map 的 key
+type ChannelKey struct {
+	ChannelID   string
+	ChannelType ChannelType
+}
+
+// String 返回 "channel_id:channel_type" 形式的字符串
+func (k ChannelKey) String() string {
+	return k.ChannelID + ":" + strconv.Itoa(int(k.ChannelType))
+}
+
 // IntranetType 表示路由地址类型
 // 0 表示外网地址，1 表示内网地址
 type IntranetType int
@@ -56,3 +69,11 @@ const (
 	// OnlyUnreadUnread 仅返回有未读消息的会话
 	OnlyUnreadUnread OnlyUnreadMode = 1
 )
+
+// MessageSyncRequest.PullMode 的取值，表示同步历史消息的拉取方向
+const (
+	// PullModeDown 向下拉取，从起始序号开始获取更早的消息
+	PullModeDown = 0
+	// PullModeUp 向上拉取，从起始序号开始获取更新的消息
+	PullModeUp = 1
+)
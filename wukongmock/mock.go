@@ -0,0 +1,136 @@
+// Package wukongmock 提供 WuKongIM Go SDK 服务接口的手写假实现，用于单元测试
+//
+// 每个假实现都有与接口方法一一对应的 XxxFunc 字段，按需设置即可；
+// 未设置的方法会返回 ErrNotStubbed，所有调用都会被记录下来以便断言
+//
+//	api := wukongmock.NewAPI()
+//	api.Message.SendMessageFunc = func(ctx context.Context, req *wukong.SendMessageRequest, opts ...wukong.CallOption) (*wukong.SendMessageResponse, error) {
+//		return &wukong.SendMessageResponse{MessageID: 1}, nil
+//	}
+//
+//	svc := NewNotifier(api) // 被测代码依赖 wukong.API 或单个服务接口
+//	...
+//	if n := api.Message.CallCount("message.SendMessage"); n != 1 {
+//		t.Fatalf("SendMessage called %d times", n)
+//	}
+package wukongmock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// ErrNotStubbed 调用了未设置 XxxFunc 的方法
+var ErrNotStubbed = errors.New("wukongmock: method not stubbed")
+
+func notStubbed(name string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, name)
+}
+
+// Call 记录一次调用
+type Call struct {
+	// Name 操作名，与 SDK 中的命名一致，例如 "message.SendMessage"
+	Name string
+	// Request 调用时传入的请求，没有请求参数的方法为 nil
+	Request any
+}
+
+// recorder 记录调用历史，嵌入到各个假实现中
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(name string, req any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Name: name, Request: req})
+}
+
+// Calls 返回按顺序记录的全部调用
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallCount 返回指定操作被调用的次数
+func (r *recorder) CallCount(name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, c := range r.calls {
+		if c.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset 清空调用记录，已设置的 XxxFunc 保持不变
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// API 是 wukong.API 的假实现，聚合了所有服务的假实现
+type API struct {
+	Route        *RouteAPI
+	Message      *MessageAPI
+	Channel      *ChannelAPI
+	User         *UserAPI
+	Conversation *ConversationAPI
+	Connection   *ConnectionAPI
+	Event        *EventAPI
+	Manager      *ManagerAPI
+	System       *SystemAPI
+}
+
+var _ wukong.API = (*API)(nil)
+
+// NewAPI 创建所有服务都未设置的假实现
+func NewAPI() *API {
+	return &API{
+		Route:        &RouteAPI{},
+		Message:      &MessageAPI{},
+		Channel:      &ChannelAPI{},
+		User:         &UserAPI{},
+		Conversation: &ConversationAPI{},
+		Connection:   &ConnectionAPI{},
+		Event:        &EventAPI{},
+		Manager:      &ManagerAPI{},
+		System:       &SystemAPI{},
+	}
+}
+
+// RouteAPI 实现 wukong.API
+func (a *API) RouteAPI() wukong.RouteAPI { return a.Route }
+
+// MessageAPI 实现 wukong.API
+func (a *API) MessageAPI() wukong.MessageAPI { return a.Message }
+
+// ChannelAPI 实现 wukong.API
+func (a *API) ChannelAPI() wukong.ChannelAPI { return a.Channel }
+
+// UserAPI 实现 wukong.API
+func (a *API) UserAPI() wukong.UserAPI { return a.User }
+
+// ConversationAPI 实现 wukong.API
+func (a *API) ConversationAPI() wukong.ConversationAPI { return a.Conversation }
+
+// ConnectionAPI 实现 wukong.API
+func (a *API) ConnectionAPI() wukong.ConnectionAPI { return a.Connection }
+
+// EventAPI 实现 wukong.API
+func (a *API) EventAPI() wukong.EventAPI { return a.Event }
+
+// ManagerAPI 实现 wukong.API
+func (a *API) ManagerAPI() wukong.ManagerAPI { return a.Manager }
+
+// SystemAPI 实现 wukong.API
+func (a *API) SystemAPI() wukong.SystemAPI { return a.System }
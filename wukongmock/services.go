@@ -0,0 +1,449 @@
+package wukongmock
+
+import (
+	"context"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// RouteAPI 是 wukong.RouteAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type RouteAPI struct {
+	recorder
+
+	GetIMAddressFunc      func(ctx context.Context, req *wukong.RouteAddressRequest, opts ...wukong.CallOption) (*wukong.RouteAddress, error)
+	BatchGetIMAddressFunc func(ctx context.Context, req *wukong.BatchRouteAddressRequest, opts ...wukong.CallOption) ([]wukong.BatchRouteAddress, error)
+}
+
+var _ wukong.RouteAPI = (*RouteAPI)(nil)
+
+// GetIMAddress 实现 wukong.RouteAPI
+func (m *RouteAPI) GetIMAddress(ctx context.Context, req *wukong.RouteAddressRequest, opts ...wukong.CallOption) (*wukong.RouteAddress, error) {
+	m.record("route.GetIMAddress", req)
+	if m.GetIMAddressFunc == nil {
+		return nil, notStubbed("route.GetIMAddress")
+	}
+	return m.GetIMAddressFunc(ctx, req, opts...)
+}
+
+// BatchGetIMAddress 实现 wukong.RouteAPI
+func (m *RouteAPI) BatchGetIMAddress(ctx context.Context, req *wukong.BatchRouteAddressRequest, opts ...wukong.CallOption) ([]wukong.BatchRouteAddress, error) {
+	m.record("route.BatchGetIMAddress", req)
+	if m.BatchGetIMAddressFunc == nil {
+		return nil, notStubbed("route.BatchGetIMAddress")
+	}
+	return m.BatchGetIMAddressFunc(ctx, req, opts...)
+}
+
+// MessageAPI 是 wukong.MessageAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type MessageAPI struct {
+	recorder
+
+	SendMessageFunc      func(ctx context.Context, req *wukong.SendMessageRequest, opts ...wukong.CallOption) (*wukong.SendMessageResponse, error)
+	BatchSendMessageFunc func(ctx context.Context, req *wukong.BatchSendMessageRequest, opts ...wukong.CallOption) ([]wukong.BatchSendMessageResponseItem, error)
+	MessageSyncFunc      func(ctx context.Context, req *wukong.MessageSyncRequest, opts ...wukong.CallOption) ([]wukong.Message, error)
+	GetMaxMessageSeqFunc func(ctx context.Context, req *wukong.MaxMessageSeqRequest, opts ...wukong.CallOption) (*wukong.MaxMessageSeqResponse, error)
+	UserSearchFunc       func(ctx context.Context, req *wukong.UserSearchRequest, opts ...wukong.CallOption) (*wukong.UserSearchResponse, error)
+	BatchSearchFunc      func(ctx context.Context, req *wukong.BatchSearchRequest, opts ...wukong.CallOption) ([]wukong.Message, error)
+	SingleSearchFunc     func(ctx context.Context, req *wukong.SingleSearchRequest, opts ...wukong.CallOption) (*wukong.Message, error)
+}
+
+var _ wukong.MessageAPI = (*MessageAPI)(nil)
+
+// SendMessage 实现 wukong.MessageAPI
+func (m *MessageAPI) SendMessage(ctx context.Context, req *wukong.SendMessageRequest, opts ...wukong.CallOption) (*wukong.SendMessageResponse, error) {
+	m.record("message.SendMessage", req)
+	if m.SendMessageFunc == nil {
+		return nil, notStubbed("message.SendMessage")
+	}
+	return m.SendMessageFunc(ctx, req, opts...)
+}
+
+// BatchSendMessage 实现 wukong.MessageAPI
+func (m *MessageAPI) BatchSendMessage(ctx context.Context, req *wukong.BatchSendMessageRequest, opts ...wukong.CallOption) ([]wukong.BatchSendMessageResponseItem, error) {
+	m.record("message.BatchSendMessage", req)
+	if m.BatchSendMessageFunc == nil {
+		return nil, notStubbed("message.BatchSendMessage")
+	}
+	return m.BatchSendMessageFunc(ctx, req, opts...)
+}
+
+// MessageSync 实现 wukong.MessageAPI
+func (m *MessageAPI) MessageSync(ctx context.Context, req *wukong.MessageSyncRequest, opts ...wukong.CallOption) ([]wukong.Message, error) {
+	m.record("message.MessageSync", req)
+	if m.MessageSyncFunc == nil {
+		return nil, notStubbed("message.MessageSync")
+	}
+	return m.MessageSyncFunc(ctx, req, opts...)
+}
+
+// GetMaxMessageSeq 实现 wukong.MessageAPI
+func (m *MessageAPI) GetMaxMessageSeq(ctx context.Context, req *wukong.MaxMessageSeqRequest, opts ...wukong.CallOption) (*wukong.MaxMessageSeqResponse, error) {
+	m.record("message.GetMaxMessageSeq", req)
+	if m.GetMaxMessageSeqFunc == nil {
+		return nil, notStubbed("message.GetMaxMessageSeq")
+	}
+	return m.GetMaxMessageSeqFunc(ctx, req, opts...)
+}
+
+// UserSearch 实现 wukong.MessageAPI
+func (m *MessageAPI) UserSearch(ctx context.Context, req *wukong.UserSearchRequest, opts ...wukong.CallOption) (*wukong.UserSearchResponse, error) {
+	m.record("message.UserSearch", req)
+	if m.UserSearchFunc == nil {
+		return nil, notStubbed("message.UserSearch")
+	}
+	return m.UserSearchFunc(ctx, req, opts...)
+}
+
+// BatchSearch 实现 wukong.MessageAPI
+func (m *MessageAPI) BatchSearch(ctx context.Context, req *wukong.BatchSearchRequest, opts ...wukong.CallOption) ([]wukong.Message, error) {
+	m.record("message.BatchSearch", req)
+	if m.BatchSearchFunc == nil {
+		return nil, notStubbed("message.BatchSearch")
+	}
+	return m.BatchSearchFunc(ctx, req, opts...)
+}
+
+// SingleSearch 实现 wukong.MessageAPI
+func (m *MessageAPI) SingleSearch(ctx context.Context, req *wukong.SingleSearchRequest, opts ...wukong.CallOption) (*wukong.Message, error) {
+	m.record("message.SingleSearch", req)
+	if m.SingleSearchFunc == nil {
+		return nil, notStubbed("message.SingleSearch")
+	}
+	return m.SingleSearchFunc(ctx, req, opts...)
+}
+
+// ChannelAPI 是 wukong.ChannelAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type ChannelAPI struct {
+	recorder
+
+	CreateFunc            func(ctx context.Context, req *wukong.CreateChannelRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	UpdateInfoFunc        func(ctx context.Context, req *wukong.UpdateInfoRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	AddSubscribersFunc    func(ctx context.Context, req *wukong.AddSubscribersRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	RemoveSubscribersFunc func(ctx context.Context, req *wukong.RemoveSubscribersRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	DeleteFunc            func(ctx context.Context, req *wukong.DeleteChannelRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	AddBlacklistFunc      func(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	SetBlacklistFunc      func(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	RemoveBlacklistFunc   func(ctx context.Context, req *wukong.RemoveBlacklistRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	AddWhitelistFunc      func(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	SetWhitelistFunc      func(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	RemoveWhitelistFunc   func(ctx context.Context, req *wukong.RemoveWhitelistRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	GetWhitelistFunc      func(ctx context.Context, req *wukong.GetWhitelistRequest, opts ...wukong.CallOption) ([]string, error)
+	SetTmpSubscriberFunc  func(ctx context.Context, req *wukong.SetTmpSubscriberRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+}
+
+var _ wukong.ChannelAPI = (*ChannelAPI)(nil)
+
+// Create 实现 wukong.ChannelAPI
+func (m *ChannelAPI) Create(ctx context.Context, req *wukong.CreateChannelRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.Create", req)
+	if m.CreateFunc == nil {
+		return nil, notStubbed("channel.Create")
+	}
+	return m.CreateFunc(ctx, req, opts...)
+}
+
+// UpdateInfo 实现 wukong.ChannelAPI
+func (m *ChannelAPI) UpdateInfo(ctx context.Context, req *wukong.UpdateInfoRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.UpdateInfo", req)
+	if m.UpdateInfoFunc == nil {
+		return nil, notStubbed("channel.UpdateInfo")
+	}
+	return m.UpdateInfoFunc(ctx, req, opts...)
+}
+
+// AddSubscribers 实现 wukong.ChannelAPI
+func (m *ChannelAPI) AddSubscribers(ctx context.Context, req *wukong.AddSubscribersRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.AddSubscribers", req)
+	if m.AddSubscribersFunc == nil {
+		return nil, notStubbed("channel.AddSubscribers")
+	}
+	return m.AddSubscribersFunc(ctx, req, opts...)
+}
+
+// RemoveSubscribers 实现 wukong.ChannelAPI
+func (m *ChannelAPI) RemoveSubscribers(ctx context.Context, req *wukong.RemoveSubscribersRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.RemoveSubscribers", req)
+	if m.RemoveSubscribersFunc == nil {
+		return nil, notStubbed("channel.RemoveSubscribers")
+	}
+	return m.RemoveSubscribersFunc(ctx, req, opts...)
+}
+
+// Delete 实现 wukong.ChannelAPI
+func (m *ChannelAPI) Delete(ctx context.Context, req *wukong.DeleteChannelRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.Delete", req)
+	if m.DeleteFunc == nil {
+		return nil, notStubbed("channel.Delete")
+	}
+	return m.DeleteFunc(ctx, req, opts...)
+}
+
+// AddBlacklist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) AddBlacklist(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.AddBlacklist", req)
+	if m.AddBlacklistFunc == nil {
+		return nil, notStubbed("channel.AddBlacklist")
+	}
+	return m.AddBlacklistFunc(ctx, req, opts...)
+}
+
+// SetBlacklist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) SetBlacklist(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.SetBlacklist", req)
+	if m.SetBlacklistFunc == nil {
+		return nil, notStubbed("channel.SetBlacklist")
+	}
+	return m.SetBlacklistFunc(ctx, req, opts...)
+}
+
+// RemoveBlacklist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) RemoveBlacklist(ctx context.Context, req *wukong.RemoveBlacklistRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.RemoveBlacklist", req)
+	if m.RemoveBlacklistFunc == nil {
+		return nil, notStubbed("channel.RemoveBlacklist")
+	}
+	return m.RemoveBlacklistFunc(ctx, req, opts...)
+}
+
+// AddWhitelist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) AddWhitelist(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.AddWhitelist", req)
+	if m.AddWhitelistFunc == nil {
+		return nil, notStubbed("channel.AddWhitelist")
+	}
+	return m.AddWhitelistFunc(ctx, req, opts...)
+}
+
+// SetWhitelist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) SetWhitelist(ctx context.Context, req *wukong.ChannelUIDsRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.SetWhitelist", req)
+	if m.SetWhitelistFunc == nil {
+		return nil, notStubbed("channel.SetWhitelist")
+	}
+	return m.SetWhitelistFunc(ctx, req, opts...)
+}
+
+// RemoveWhitelist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) RemoveWhitelist(ctx context.Context, req *wukong.RemoveWhitelistRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.RemoveWhitelist", req)
+	if m.RemoveWhitelistFunc == nil {
+		return nil, notStubbed("channel.RemoveWhitelist")
+	}
+	return m.RemoveWhitelistFunc(ctx, req, opts...)
+}
+
+// GetWhitelist 实现 wukong.ChannelAPI
+func (m *ChannelAPI) GetWhitelist(ctx context.Context, req *wukong.GetWhitelistRequest, opts ...wukong.CallOption) ([]string, error) {
+	m.record("channel.GetWhitelist", req)
+	if m.GetWhitelistFunc == nil {
+		return nil, notStubbed("channel.GetWhitelist")
+	}
+	return m.GetWhitelistFunc(ctx, req, opts...)
+}
+
+// SetTmpSubscriber 实现 wukong.ChannelAPI
+func (m *ChannelAPI) SetTmpSubscriber(ctx context.Context, req *wukong.SetTmpSubscriberRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("channel.SetTmpSubscriber", req)
+	if m.SetTmpSubscriberFunc == nil {
+		return nil, notStubbed("channel.SetTmpSubscriber")
+	}
+	return m.SetTmpSubscriberFunc(ctx, req, opts...)
+}
+
+// UserAPI 是 wukong.UserAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type UserAPI struct {
+	recorder
+
+	UpdateTokenFunc      func(ctx context.Context, req *wukong.UpdateUserTokenRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	DeviceQuitFunc       func(ctx context.Context, req *wukong.DeviceQuitRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	OnlineStatusFunc     func(ctx context.Context, req *wukong.OnlineStatusRequest, opts ...wukong.CallOption) ([]wukong.UserOnlineStatus, error)
+	SystemUIDsFunc       func(ctx context.Context, opts ...wukong.CallOption) ([]string, error)
+	AddSystemUIDsFunc    func(ctx context.Context, req *wukong.SystemUIDsChangeRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	RemoveSystemUIDsFunc func(ctx context.Context, req *wukong.SystemUIDsChangeRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+}
+
+var _ wukong.UserAPI = (*UserAPI)(nil)
+
+// UpdateToken 实现 wukong.UserAPI
+func (m *UserAPI) UpdateToken(ctx context.Context, req *wukong.UpdateUserTokenRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("user.UpdateToken", req)
+	if m.UpdateTokenFunc == nil {
+		return nil, notStubbed("user.UpdateToken")
+	}
+	return m.UpdateTokenFunc(ctx, req, opts...)
+}
+
+// DeviceQuit 实现 wukong.UserAPI
+func (m *UserAPI) DeviceQuit(ctx context.Context, req *wukong.DeviceQuitRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("user.DeviceQuit", req)
+	if m.DeviceQuitFunc == nil {
+		return nil, notStubbed("user.DeviceQuit")
+	}
+	return m.DeviceQuitFunc(ctx, req, opts...)
+}
+
+// OnlineStatus 实现 wukong.UserAPI
+func (m *UserAPI) OnlineStatus(ctx context.Context, req *wukong.OnlineStatusRequest, opts ...wukong.CallOption) ([]wukong.UserOnlineStatus, error) {
+	m.record("user.OnlineStatus", req)
+	if m.OnlineStatusFunc == nil {
+		return nil, notStubbed("user.OnlineStatus")
+	}
+	return m.OnlineStatusFunc(ctx, req, opts...)
+}
+
+// SystemUIDs 实现 wukong.UserAPI
+func (m *UserAPI) SystemUIDs(ctx context.Context, opts ...wukong.CallOption) ([]string, error) {
+	m.record("user.SystemUIDs", nil)
+	if m.SystemUIDsFunc == nil {
+		return nil, notStubbed("user.SystemUIDs")
+	}
+	return m.SystemUIDsFunc(ctx, opts...)
+}
+
+// AddSystemUIDs 实现 wukong.UserAPI
+func (m *UserAPI) AddSystemUIDs(ctx context.Context, req *wukong.SystemUIDsChangeRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("user.AddSystemUIDs", req)
+	if m.AddSystemUIDsFunc == nil {
+		return nil, notStubbed("user.AddSystemUIDs")
+	}
+	return m.AddSystemUIDsFunc(ctx, req, opts...)
+}
+
+// RemoveSystemUIDs 实现 wukong.UserAPI
+func (m *UserAPI) RemoveSystemUIDs(ctx context.Context, req *wukong.SystemUIDsChangeRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("user.RemoveSystemUIDs", req)
+	if m.RemoveSystemUIDsFunc == nil {
+		return nil, notStubbed("user.RemoveSystemUIDs")
+	}
+	return m.RemoveSystemUIDsFunc(ctx, req, opts...)
+}
+
+// ConversationAPI 是 wukong.ConversationAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type ConversationAPI struct {
+	recorder
+
+	SyncFunc        func(ctx context.Context, req *wukong.ConversationSyncRequest, opts ...wukong.CallOption) ([]wukong.Conversation, error)
+	ClearUnreadFunc func(ctx context.Context, req *wukong.ConversationClearUnreadRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	SetUnreadFunc   func(ctx context.Context, req *wukong.ConversationSetUnreadRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	DeleteFunc      func(ctx context.Context, req *wukong.ConversationDeleteRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+}
+
+var _ wukong.ConversationAPI = (*ConversationAPI)(nil)
+
+// Sync 实现 wukong.ConversationAPI
+func (m *ConversationAPI) Sync(ctx context.Context, req *wukong.ConversationSyncRequest, opts ...wukong.CallOption) ([]wukong.Conversation, error) {
+	m.record("conversation.Sync", req)
+	if m.SyncFunc == nil {
+		return nil, notStubbed("conversation.Sync")
+	}
+	return m.SyncFunc(ctx, req, opts...)
+}
+
+// ClearUnread 实现 wukong.ConversationAPI
+func (m *ConversationAPI) ClearUnread(ctx context.Context, req *wukong.ConversationClearUnreadRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("conversation.ClearUnread", req)
+	if m.ClearUnreadFunc == nil {
+		return nil, notStubbed("conversation.ClearUnread")
+	}
+	return m.ClearUnreadFunc(ctx, req, opts...)
+}
+
+// SetUnread 实现 wukong.ConversationAPI
+func (m *ConversationAPI) SetUnread(ctx context.Context, req *wukong.ConversationSetUnreadRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("conversation.SetUnread", req)
+	if m.SetUnreadFunc == nil {
+		return nil, notStubbed("conversation.SetUnread")
+	}
+	return m.SetUnreadFunc(ctx, req, opts...)
+}
+
+// Delete 实现 wukong.ConversationAPI
+func (m *ConversationAPI) Delete(ctx context.Context, req *wukong.ConversationDeleteRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("conversation.Delete", req)
+	if m.DeleteFunc == nil {
+		return nil, notStubbed("conversation.Delete")
+	}
+	return m.DeleteFunc(ctx, req, opts...)
+}
+
+// ConnectionAPI 是 wukong.ConnectionAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type ConnectionAPI struct {
+	recorder
+
+	RemoveFunc func(ctx context.Context, req *wukong.ConnectionRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+	KickFunc   func(ctx context.Context, req *wukong.ConnectionRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+}
+
+var _ wukong.ConnectionAPI = (*ConnectionAPI)(nil)
+
+// Remove 实现 wukong.ConnectionAPI
+func (m *ConnectionAPI) Remove(ctx context.Context, req *wukong.ConnectionRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("connection.Remove", req)
+	if m.RemoveFunc == nil {
+		return nil, notStubbed("connection.Remove")
+	}
+	return m.RemoveFunc(ctx, req, opts...)
+}
+
+// Kick 实现 wukong.ConnectionAPI
+func (m *ConnectionAPI) Kick(ctx context.Context, req *wukong.ConnectionRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("connection.Kick", req)
+	if m.KickFunc == nil {
+		return nil, notStubbed("connection.Kick")
+	}
+	return m.KickFunc(ctx, req, opts...)
+}
+
+// EventAPI 是 wukong.EventAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type EventAPI struct {
+	recorder
+
+	SendFunc func(ctx context.Context, req *wukong.EventSendRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error)
+}
+
+var _ wukong.EventAPI = (*EventAPI)(nil)
+
+// Send 实现 wukong.EventAPI
+func (m *EventAPI) Send(ctx context.Context, req *wukong.EventSendRequest, opts ...wukong.CallOption) (*wukong.CreateChannelResponse, error) {
+	m.record("event.Send", req)
+	if m.SendFunc == nil {
+		return nil, notStubbed("event.Send")
+	}
+	return m.SendFunc(ctx, req, opts...)
+}
+
+// ManagerAPI 是 wukong.ManagerAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type ManagerAPI struct {
+	recorder
+
+	LoginFunc func(ctx context.Context, req *wukong.ManagerLoginRequest, opts ...wukong.CallOption) (*wukong.ManagerLoginResponse, error)
+}
+
+var _ wukong.ManagerAPI = (*ManagerAPI)(nil)
+
+// Login 实现 wukong.ManagerAPI
+func (m *ManagerAPI) Login(ctx context.Context, req *wukong.ManagerLoginRequest, opts ...wukong.CallOption) (*wukong.ManagerLoginResponse, error) {
+	m.record("manager.Login", req)
+	if m.LoginFunc == nil {
+		return nil, notStubbed("manager.Login")
+	}
+	return m.LoginFunc(ctx, req, opts...)
+}
+
+// SystemAPI 是 wukong.SystemAPI 的假实现，未设置的方法返回 ErrNotStubbed
+type SystemAPI struct {
+	recorder
+
+	HealthFunc func(ctx context.Context, opts ...wukong.CallOption) (*wukong.HealthStatus, error)
+}
+
+var _ wukong.SystemAPI = (*SystemAPI)(nil)
+
+// Health 实现 wukong.SystemAPI
+func (m *SystemAPI) Health(ctx context.Context, opts ...wukong.CallOption) (*wukong.HealthStatus, error) {
+	m.record("system.Health", nil)
+	if m.HealthFunc == nil {
+		return nil, notStubbed("system.Health")
+	}
+	return m.HealthFunc(ctx, opts...)
+}
@@ -0,0 +1,145 @@
+package wukongtest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault 描述一次故障注入
+//
+//	// /message/send 前两次请求返回 503
+//	srv.InjectFault(wukongtest.Fault{Path: "/message/send", Status: http.StatusServiceUnavailable, Times: 2})
+//	// 所有请求增加 50ms 延迟，并有 10% 的概率返回 500
+//	srv.InjectFault(wukongtest.Fault{Latency: 50 * time.Millisecond})
+//	srv.InjectFault(wukongtest.Fault{Status: http.StatusInternalServerError, Rate: 0.1})
+type Fault struct {
+	// Path 匹配的请求路径（不含查询参数），为空匹配所有请求；以 "/" 结尾时按前缀匹配
+	Path string
+	// Method 匹配的请求方法，为空匹配所有方法
+	Method string
+
+	// Latency 在处理请求前等待的时间
+	Latency time.Duration
+	// Status 返回的 HTTP 状态码，为 0 时只注入延迟，请求继续正常处理
+	Status int
+	// Message 错误响应中的 msg，默认使用状态码对应的文本
+	Message string
+	// RetryAfter 设置 Retry-After 响应头，常与 429 一起使用
+	RetryAfter time.Duration
+	// Drop 为 true 时直接断开连接，模拟网络错误
+	Drop bool
+
+	// Rate 故障生效的概率，取值 (0, 1]，为 0 时总是生效
+	Rate float64
+	// Times 故障生效的次数，用完后自动移除，为 0 时一直生效
+	Times int
+}
+
+// InjectFault 注入一个故障，多个故障按注入顺序依次匹配
+func (s *Server) InjectFault(f Fault) {
+	s.faults.add(f)
+}
+
+// ClearFaults 移除所有故障
+func (s *Server) ClearFaults() {
+	s.faults.clear()
+}
+
+type faults struct {
+	mu   sync.Mutex
+	list []*activeFault
+}
+
+type activeFault struct {
+	Fault
+	remaining int
+}
+
+func (fs *faults) add(f Fault) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.list = append(fs.list, &activeFault{Fault: f, remaining: f.Times})
+}
+
+func (fs *faults) clear() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.list = nil
+}
+
+// match 返回本次请求命中的故障，并扣减生效次数
+func (fs *faults) match(r *http.Request) []Fault {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var hit []Fault
+	kept := fs.list[:0]
+	for _, f := range fs.list {
+		if f.matches(r) && (f.Rate <= 0 || rand.Float64() < f.Rate) {
+			hit = append(hit, f.Fault)
+			if f.Times > 0 {
+				f.remaining--
+				if f.remaining <= 0 {
+					continue
+				}
+			}
+		}
+		kept = append(kept, f)
+	}
+	fs.list = kept
+	return hit
+}
+
+func (f *activeFault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	switch {
+	case f.Path == "":
+		return true
+	case strings.HasSuffix(f.Path, "/"):
+		return strings.HasPrefix(r.URL.Path, f.Path)
+	default:
+		return r.URL.Path == f.Path
+	}
+}
+
+// apply 应用命中的故障，返回 true 表示已经写入响应，不再继续处理
+func (fs *faults) apply(w http.ResponseWriter, r *http.Request) bool {
+	for _, f := range fs.match(r) {
+		if f.Latency > 0 {
+			select {
+			case <-time.After(f.Latency):
+			case <-r.Context().Done():
+				return true
+			}
+		}
+
+		if f.Drop {
+			if hj, ok := w.(http.Hijacker); ok {
+				if conn, _, err := hj.Hijack(); err == nil {
+					_ = conn.Close()
+					return true
+				}
+			}
+			panic(http.ErrAbortHandler)
+		}
+
+		if f.Status != 0 {
+			if f.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second)/time.Second)))
+			}
+			msg := f.Message
+			if msg == "" {
+				msg = http.StatusText(f.Status)
+			}
+			writeError(w, f.Status, msg)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,842 @@
+package wukongtest
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, wukong.HealthStatus{Status: "ok"})
+}
+
+func (s *Server) handleRoute(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.route)
+}
+
+func (s *Server) handleRouteBatch(w http.ResponseWriter, r *http.Request) {
+	var uids []string
+	if !decode(w, r, &uids) {
+		return
+	}
+	writeJSON(w, http.StatusOK, []wukong.BatchRouteAddress{{
+		UIDs:    uids,
+		TCPAddr: s.route.TCPAddr,
+		WSAddr:  s.route.WSAddr,
+		WSSAddr: s.route.WSSAddr,
+	}})
+}
+
+// ---------------- 消息 ----------------
+
+// validateSend 校验发送请求，返回错误状态码与信息，调用方需持有锁
+func (s *Server) validateSend(req *wukong.SendMessageRequest) (int, string) {
+	if req.ChannelID == "" {
+		return http.StatusBadRequest, "channel_id 参数不能为空"
+	}
+	if req.ChannelType == 0 {
+		return http.StatusBadRequest, "channel_type 参数不能为空"
+	}
+	if _, err := base64.StdEncoding.DecodeString(req.Payload); err != nil {
+		return http.StatusBadRequest, "payload 必须为 base64 编码"
+	}
+
+	key := channelKey{req.ChannelID, req.ChannelType}
+	ch, ok := s.state.channels[key]
+	if !ok && req.ChannelType != wukong.ChannelTypePerson {
+		return http.StatusNotFound, "频道不存在"
+	}
+
+	if ch == nil || req.FromUID == "" || s.state.isSystemUID(req.FromUID) {
+		return 0, ""
+	}
+	switch {
+	case ch.ban == 1:
+		return http.StatusForbidden, "频道已被禁言"
+	case ch.blacklist.contains(req.FromUID):
+		return http.StatusForbidden, "发送者在频道黑名单中"
+	case len(ch.whitelist) > 0 && !ch.whitelist.contains(req.FromUID):
+		return http.StatusForbidden, "发送者不在频道白名单中"
+	}
+	return 0, ""
+}
+
+// send 发送消息，调用方需持有锁且已通过 validateSend
+// 与 WuKongIM 一样，同一频道内重复的 ClientMsgNo 不会再次保存，直接返回第一次发送的结果
+func (s *Server) send(req *wukong.SendMessageRequest) wukong.SendMessageResponse {
+	if req.ClientMsgNo == "" {
+		return s.store(req)
+	}
+
+	key := sentKey{storageKey(req.FromUID, channelKey{req.ChannelID, req.ChannelType}), req.ClientMsgNo}
+	if resp, ok := s.state.sent[key]; ok {
+		return resp
+	}
+	resp := s.store(req)
+	s.state.sent[key] = resp
+	return resp
+}
+
+// store 保存消息并更新相关会话，调用方需持有锁
+func (s *Server) store(req *wukong.SendMessageRequest) wukong.SendMessageResponse {
+	st := s.state
+	st.nextMessageID++
+
+	msg := wukong.Message{
+		MessageID:   st.nextMessageID,
+		ClientMsgNo: req.ClientMsgNo,
+		FromUID:     req.FromUID,
+		ChannelID:   req.ChannelID,
+		ChannelType: req.ChannelType,
+		Timestamp:   s.now().Unix(),
+		Payload:     req.Payload,
+	}
+	if msg.ClientMsgNo == "" {
+		msg.ClientMsgNo = fmt.Sprintf("wukongtest-%d", msg.MessageID)
+	}
+
+	resp := wukong.SendMessageResponse{MessageID: msg.MessageID, ClientMsgNo: msg.ClientMsgNo}
+	if req.Header != nil && req.Header.NoPersist == 1 {
+		return resp
+	}
+
+	key := channelKey{req.ChannelID, req.ChannelType}
+	sk := storageKey(req.FromUID, key)
+	msg.MessageSeq = maxSeq(st.messages[sk]) + 1
+	st.messages[sk] = append(st.messages[sk], msg)
+	st.messagesByID[msg.MessageID] = msg
+	resp.MessageSeq = msg.MessageSeq
+
+	// 收到消息的用户及其视角下的会话
+	type participant struct {
+		uid string
+		key channelKey
+	}
+	var participants []participant
+	if req.ChannelType == wukong.ChannelTypePerson {
+		if req.FromUID != "" {
+			participants = append(participants,
+				participant{req.FromUID, key},
+				participant{req.ChannelID, channelKey{req.FromUID, wukong.ChannelTypePerson}},
+			)
+		}
+	} else if ch := st.channels[key]; ch != nil {
+		for _, uid := range uidSet(ch.subscribers.clone()).add(ch.tmpSubscribers...) {
+			participants = append(participants, participant{uid, key})
+		}
+	}
+
+	redDot := req.Header == nil || req.Header.RedDot == 1
+	version := st.nextVersion()
+	for _, p := range participants {
+		conv := st.conversationOf(p.uid, p.key)
+		conv.lastMsgSeq = msg.MessageSeq
+		conv.timestamp = msg.Timestamp
+		conv.version = version
+		if p.uid != req.FromUID && redDot {
+			conv.unread++
+		}
+	}
+	return resp
+}
+
+func (s *Server) handleMessageSend(w http.ResponseWriter, r *http.Request) {
+	var req wukong.SendMessageRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	if code, msg := s.validateSend(&req); code != 0 {
+		writeError(w, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.send(&req))
+}
+
+// handleMessageSendBatch 批量发送，任意一条校验失败时整批都不会发送
+func (s *Server) handleMessageSendBatch(w http.ResponseWriter, r *http.Request) {
+	var reqs []wukong.SendMessageRequest
+	if !decode(w, r, &reqs) {
+		return
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	for i := range reqs {
+		if code, msg := s.validateSend(&reqs[i]); code != 0 {
+			writeError(w, code, fmt.Sprintf("messages[%d]: %s", i, msg))
+			return
+		}
+	}
+
+	out := make([]wukong.BatchSendMessageResponseItem, 0, len(reqs))
+	for i := range reqs {
+		resp := s.send(&reqs[i])
+		out = append(out, wukong.BatchSendMessageResponseItem(resp))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleMessageSync 按 pull_mode 拉取消息：0 从 start_message_seq 向下（更早），1 向上（更新）
+// 返回的消息始终按 MessageSeq 升序
+func (s *Server) handleMessageSync(w http.ResponseWriter, r *http.Request) {
+	var req wukong.MessageSyncRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.ChannelID == "" {
+		writeError(w, http.StatusBadRequest, "channel_id 参数不能为空")
+		return
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	s.state.mu.Lock()
+	msgs := s.state.messages[storageKey(req.LoginUID, channelKey{req.ChannelID, req.ChannelType})]
+	s.state.mu.Unlock()
+
+	out := []wukong.Message{}
+	if req.PullMode == 1 {
+		for _, m := range msgs {
+			if m.MessageSeq < req.StartMessageSeq || (req.EndMessageSeq > 0 && m.MessageSeq > req.EndMessageSeq) {
+				continue
+			}
+			if len(out) == limit {
+				break
+			}
+			out = append(out, m)
+		}
+	} else {
+		start := req.StartMessageSeq
+		if start <= 0 {
+			start = maxSeq(msgs)
+		}
+		for i := len(msgs) - 1; i >= 0 && len(out) < limit; i-- {
+			m := msgs[i]
+			if m.MessageSeq > start || (req.EndMessageSeq > 0 && m.MessageSeq < req.EndMessageSeq) {
+				continue
+			}
+			out = append(out, m)
+		}
+		slices.Reverse(out)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleMaxMessageSeq(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	channelID := q.Get("channel_id")
+	if channelID == "" {
+		writeError(w, http.StatusBadRequest, "channel_id 参数不能为空")
+		return
+	}
+	channelType, _ := strconv.Atoi(q.Get("channel_type"))
+
+	key := storageKey(q.Get("login_uid"), channelKey{channelID, wukong.ChannelType(channelType)})
+
+	s.state.mu.Lock()
+	seq := maxSeq(s.state.messages[key])
+	s.state.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, wukong.MaxMessageSeqResponse{MaxMessageSeq: seq})
+}
+
+// handleUserSearch 返回用户可见的消息，按时间倒序分页
+// 支持 channel_type 与 payload_types 过滤，payload_types 匹配 payload 解码后 JSON 中的 type 字段
+func (s *Server) handleUserSearch(w http.ResponseWriter, r *http.Request) {
+	var req wukong.UserSearchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.UID == "" {
+		writeError(w, http.StatusBadRequest, "uid 参数不能为空")
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := max(req.Page, 1)
+
+	s.state.mu.Lock()
+	var matched []wukong.UserSearchResult
+	for sk, msgs := range s.state.messages {
+		if !s.visibleTo(req.UID, sk) || (req.ChannelType != 0 && sk.typ != req.ChannelType) {
+			continue
+		}
+		for _, m := range msgs {
+			payload := decodePayload(m.Payload)
+			if len(req.PayloadTypes) > 0 && !slices.Contains(req.PayloadTypes, payloadType(payload)) {
+				continue
+			}
+			matched = append(matched, wukong.UserSearchResult{
+				MessageID:    m.MessageID,
+				MessageIDStr: strconv.FormatInt(m.MessageID, 10),
+				MessageSeq:   m.MessageSeq,
+				ClientMsgNo:  m.ClientMsgNo,
+				FromUID:      m.FromUID,
+				ChannelID:    m.ChannelID,
+				ChannelType:  m.ChannelType,
+				Payload:      payload,
+				Timestamp:    m.Timestamp,
+			})
+		}
+	}
+	s.state.mu.Unlock()
+
+	slices.SortFunc(matched, func(a, b wukong.UserSearchResult) int {
+		return cmp.Compare(b.MessageID, a.MessageID)
+	})
+
+	resp := wukong.UserSearchResponse{Total: len(matched), Limit: limit, Page: page, Messages: []wukong.UserSearchResult{}}
+	if from := (page - 1) * limit; from < len(matched) {
+		resp.Messages = matched[from:min(from+limit, len(matched))]
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// visibleTo 判断用户能否看到某个存储频道中的消息，调用方需持有锁
+func (s *Server) visibleTo(uid string, sk channelKey) bool {
+	if sk.typ == wukong.ChannelTypePerson {
+		a, b, _ := strings.Cut(sk.id, "@")
+		return uid == a || uid == b
+	}
+	ch, ok := s.state.channels[sk]
+	return ok && (ch.subscribers.contains(uid) || ch.tmpSubscribers.contains(uid))
+}
+
+func decodePayload(payload string) map[string]any {
+	raw, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func payloadType(payload map[string]any) int {
+	t, _ := payload["type"].(float64)
+	return int(t)
+}
+
+func (s *Server) handleBatchSearch(w http.ResponseWriter, r *http.Request) {
+	var req wukong.BatchSearchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	out := []wukong.Message{}
+	for _, id := range req.MessageIDs {
+		if m, ok := s.state.messagesByID[id]; ok {
+			out = append(out, m)
+		}
+	}
+	s.state.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleSingleSearch(w http.ResponseWriter, r *http.Request) {
+	var req wukong.SingleSearchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	m, ok := s.state.messagesByID[req.MessageID]
+	s.state.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "消息不存在")
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// ---------------- 频道 ----------------
+
+// channelRequest 频道类请求共有的字段
+type channelRequest struct {
+	ChannelID   string             `json:"channel_id"`
+	ChannelType wukong.ChannelType `json:"channel_type"`
+}
+
+func (c channelRequest) validate(w http.ResponseWriter) bool {
+	if c.ChannelID == "" {
+		writeError(w, http.StatusBadRequest, "channel_id 参数不能为空")
+		return false
+	}
+	if c.ChannelType == 0 {
+		writeError(w, http.StatusBadRequest, "channel_type 参数不能为空")
+		return false
+	}
+	return true
+}
+
+func (c channelRequest) key() channelKey {
+	return channelKey{c.ChannelID, c.ChannelType}
+}
+
+// handleChannelCreate 创建频道，频道已存在时覆盖配置与订阅者
+func (s *Server) handleChannelCreate(w http.ResponseWriter, r *http.Request) {
+	var req wukong.CreateChannelRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	ch := s.state.channelOrCreate(channelKey{req.ChannelID, req.ChannelType})
+	ch.large = req.Large
+	ch.ban = req.Ban
+	ch.subscribers = uidSet(nil).add(req.Subscribers...)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleChannelInfo(w http.ResponseWriter, r *http.Request) {
+	var req wukong.UpdateInfoRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	ch := s.state.channelOrCreate(channelKey{req.ChannelID, req.ChannelType})
+	if req.Large != nil {
+		ch.large = *req.Large
+	}
+	if req.Ban != nil {
+		ch.ban = *req.Ban
+	}
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+// handleChannelDelete 删除频道及其消息
+func (s *Server) handleChannelDelete(w http.ResponseWriter, r *http.Request) {
+	var req channelRequest
+	if !decode(w, r, &req) || !req.validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	key := req.key()
+	delete(s.state.channels, key)
+	for _, m := range s.state.messages[key] {
+		delete(s.state.messagesByID, m.MessageID)
+	}
+	delete(s.state.messages, key)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleSubscriberAdd(w http.ResponseWriter, r *http.Request) {
+	var req wukong.AddSubscribersRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	ch := s.state.channelOrCreate(channelKey{req.ChannelID, req.ChannelType})
+	list := &ch.subscribers
+	if req.TempSubscriber == 1 {
+		list = &ch.tmpSubscribers
+	}
+	if req.Reset == 1 {
+		*list = nil
+	}
+	*list = list.add(req.Subscribers...)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleSubscriberRemove(w http.ResponseWriter, r *http.Request) {
+	var req wukong.RemoveSubscribersRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	if ch, exists := s.state.channels[channelKey{req.ChannelID, req.ChannelType}]; exists {
+		if req.TempSubscriber == 1 {
+			ch.tmpSubscribers = ch.tmpSubscribers.remove(req.Subscribers...)
+		} else {
+			ch.subscribers = ch.subscribers.remove(req.Subscribers...)
+		}
+	}
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleTmpSubscriberSet(w http.ResponseWriter, r *http.Request) {
+	var req wukong.SetTmpSubscriberRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	ch := s.state.channelOrCreate(channelKey{req.ChannelID, req.ChannelType})
+	ch.tmpSubscribers = uidSet(nil).add(req.Subscribers...)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+type uidList int
+
+const (
+	listBlacklist uidList = iota
+	listWhitelist
+)
+
+type listOp int
+
+const (
+	opAdd listOp = iota
+	opSet
+	opRemove
+)
+
+// handleUIDList 处理黑名单/白名单的添加、设置与移除
+func (s *Server) handleUIDList(which uidList, op listOp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req wukong.ChannelUIDsRequest
+		if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+			return
+		}
+
+		s.state.mu.Lock()
+		ch := s.state.channelOrCreate(channelKey{req.ChannelID, req.ChannelType})
+		list := &ch.blacklist
+		if which == listWhitelist {
+			list = &ch.whitelist
+		}
+		switch op {
+		case opAdd:
+			*list = list.add(req.UIDs...)
+		case opSet:
+			*list = uidSet(nil).add(req.UIDs...)
+		case opRemove:
+			*list = list.remove(req.UIDs...)
+		}
+		s.state.mu.Unlock()
+
+		writeOK(w)
+	}
+}
+
+func (s *Server) handleWhitelistGet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	channelType, _ := strconv.Atoi(q.Get("channel_type"))
+	req := channelRequest{q.Get("channel_id"), wukong.ChannelType(channelType)}
+	if !req.validate(w) {
+		return
+	}
+
+	s.state.mu.Lock()
+	out := []string{}
+	if ch, exists := s.state.channels[req.key()]; exists {
+		out = ch.whitelist.clone()
+	}
+	s.state.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+// ---------------- 用户 ----------------
+
+func (s *Server) handleUserToken(w http.ResponseWriter, r *http.Request) {
+	var req wukong.UpdateUserTokenRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.UID == "" {
+		writeError(w, http.StatusBadRequest, "uid 参数不能为空")
+		return
+	}
+
+	s.state.mu.Lock()
+	tokens, exists := s.state.tokens[req.UID]
+	if !exists {
+		tokens = map[int]string{}
+		s.state.tokens[req.UID] = tokens
+	}
+	tokens[req.DeviceFlag] = req.Token
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+// handleDeviceQuit 让设备下线，device_flag 为负数时所有设备下线
+func (s *Server) handleDeviceQuit(w http.ResponseWriter, r *http.Request) {
+	var req wukong.DeviceQuitRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	if req.DeviceFlag < 0 {
+		delete(s.state.online, req.UID)
+	} else {
+		delete(s.state.online[req.UID], req.DeviceFlag)
+	}
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+// handleOnlineStatus 只返回在线的用户设备
+func (s *Server) handleOnlineStatus(w http.ResponseWriter, r *http.Request) {
+	var uids []string
+	if !decode(w, r, &uids) {
+		return
+	}
+
+	s.state.mu.Lock()
+	out := []wukong.UserOnlineStatus{}
+	for _, uid := range uids {
+		devices := s.state.online[uid]
+		flags := make([]int, 0, len(devices))
+		for flag := range devices {
+			flags = append(flags, flag)
+		}
+		slices.Sort(flags)
+		for _, flag := range flags {
+			out = append(out, wukong.UserOnlineStatus{UID: uid, Online: wukong.OnlineStatusOnline, DeviceFlag: flag})
+		}
+	}
+	s.state.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleSystemUIDs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.SystemUIDs())
+}
+
+func (s *Server) handleSystemUIDsChange(add bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req wukong.SystemUIDsChangeRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		s.state.mu.Lock()
+		if add {
+			s.state.systemUIDs = s.state.systemUIDs.add(req.UIDs...)
+		} else {
+			s.state.systemUIDs = s.state.systemUIDs.remove(req.UIDs...)
+		}
+		s.state.mu.Unlock()
+
+		writeOK(w)
+	}
+}
+
+// ---------------- 会话 ----------------
+
+// handleConversationSync 返回用户的会话，按最后一条消息的时间倒序
+// 支持 version、last_msg_seqs、only_unread、exclude_channel_types 过滤，recents 按时间倒序
+func (s *Server) handleConversationSync(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ConversationSyncRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.UID == "" {
+		writeError(w, http.StatusBadRequest, "uid 参数不能为空")
+		return
+	}
+	lastSeqs := parseLastMsgSeqs(req.LastMsgSeqs)
+
+	s.state.mu.Lock()
+	out := []wukong.Conversation{}
+	for key, conv := range s.state.conversations[req.UID] {
+		switch {
+		case slices.Contains(req.ExcludeChannelTypes, key.typ):
+			continue
+		case req.OnlyUnread == wukong.OnlyUnreadUnread && conv.unread == 0:
+			continue
+		case req.Version > 0 && conv.version <= req.Version:
+			continue
+		}
+		if seq, exists := lastSeqs[key]; exists && conv.lastMsgSeq <= seq {
+			continue
+		}
+
+		c := wukong.Conversation{
+			ChannelID:   key.id,
+			ChannelType: key.typ,
+			Unread:      conv.unread,
+			Timestamp:   conv.timestamp,
+			LastMsgSeq:  conv.lastMsgSeq,
+			Version:     conv.version,
+			Recents:     []wukong.ConversationRecentMessage{},
+		}
+		msgs := s.state.messages[storageKey(req.UID, key)]
+		for i := len(msgs) - 1; i >= 0 && len(c.Recents) < req.MsgCount; i-- {
+			m := msgs[i]
+			c.Recents = append(c.Recents, wukong.ConversationRecentMessage{
+				MessageID:   m.MessageID,
+				MessageSeq:  m.MessageSeq,
+				ClientMsgNo: m.ClientMsgNo,
+				FromUID:     m.FromUID,
+				Timestamp:   m.Timestamp,
+				Payload:     m.Payload,
+			})
+		}
+		out = append(out, c)
+	}
+	s.state.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b wukong.Conversation) int {
+		return cmp.Or(cmp.Compare(b.Timestamp, a.Timestamp), cmp.Compare(b.Version, a.Version))
+	})
+	writeJSON(w, http.StatusOK, out)
+}
+
+// parseLastMsgSeqs 解析 "channel_id:channel_type:last_msg_seq|..." 格式的参数
+func parseLastMsgSeqs(s string) map[channelKey]int64 {
+	out := map[channelKey]int64{}
+	for _, item := range strings.Split(s, "|") {
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		typ, err1 := strconv.Atoi(parts[1])
+		seq, err2 := strconv.ParseInt(parts[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		out[channelKey{parts[0], wukong.ChannelType(typ)}] = seq
+	}
+	return out
+}
+
+func (s *Server) handleClearUnread(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ConversationClearUnreadRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	if conv, exists := s.state.conversations[req.UID][channelKey{req.ChannelID, req.ChannelType}]; exists {
+		conv.unread = 0
+		conv.version = s.state.nextVersion()
+	}
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleSetUnread(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ConversationSetUnreadRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	conv := s.state.conversationOf(req.UID, channelKey{req.ChannelID, req.ChannelType})
+	conv.unread = req.Unread
+	conv.version = s.state.nextVersion()
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleConversationDelete(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ConversationDeleteRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.state.mu.Lock()
+	delete(s.state.conversations[req.UID], channelKey{req.ChannelID, req.ChannelType})
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+// ---------------- 连接、事件、管理员 ----------------
+
+// handleConnection 移除或踢出连接，用户的所有设备都会下线
+func (s *Server) handleConnection(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ConnectionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.UID == "" {
+		writeError(w, http.StatusBadRequest, "uid 参数不能为空")
+		return
+	}
+
+	s.state.mu.Lock()
+	delete(s.state.online, req.UID)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	var req wukong.EventSendRequest
+	if !decode(w, r, &req) || !(channelRequest{req.ChannelID, req.ChannelType}).validate(w) {
+		return
+	}
+	if req.Event.Type == "" {
+		writeError(w, http.StatusBadRequest, "event.type 参数不能为空")
+		return
+	}
+	if v := r.URL.Query().Get("force_end"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.ForceEnd = &n
+		}
+	}
+
+	s.state.mu.Lock()
+	s.state.events = append(s.state.events, req)
+	s.state.mu.Unlock()
+
+	writeOK(w)
+}
+
+func (s *Server) handleManagerLogin(w http.ResponseWriter, r *http.Request) {
+	var req wukong.ManagerLoginRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Username != s.managerUser || req.Password != s.managerPass {
+		writeError(w, http.StatusUnauthorized, "用户名或密码错误")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wukong.ManagerLoginResponse{
+		Token:  "wukongtest-manager-token",
+		Expire: 86400,
+		User: wukong.ManagerUser{
+			Username:    req.Username,
+			Role:        "admin",
+			Permissions: []string{"*"},
+		},
+	})
+}
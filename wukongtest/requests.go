@@ -0,0 +1,134 @@
+package wukongtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Request 服务端收到的一次请求
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+}
+
+// Decode 把请求体解析到 v
+func (r Request) Decode(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+func (rec *recorder) record(r *http.Request, body []byte, now time.Time) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = append(rec.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   now,
+	})
+}
+
+func (rec *recorder) reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = nil
+}
+
+// Requests 返回按顺序记录的全部请求，包括被故障注入拦截的请求
+func (s *Server) Requests() []Request {
+	s.requests.mu.Lock()
+	defer s.requests.mu.Unlock()
+	return append([]Request(nil), s.requests.requests...)
+}
+
+// RequestsTo 返回发往指定路径的请求
+func (s *Server) RequestsTo(path string) []Request {
+	var out []Request
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// LastRequest 返回发往指定路径的最后一个请求
+func (s *Server) LastRequest(path string) (Request, bool) {
+	reqs := s.RequestsTo(path)
+	if len(reqs) == 0 {
+		return Request{}, false
+	}
+	return reqs[len(reqs)-1], true
+}
+
+// ResetRequests 清空请求记录
+func (s *Server) ResetRequests() {
+	s.requests.reset()
+}
+
+// AssertCalled 断言指定路径至少收到过一次请求
+func (s *Server) AssertCalled(t testing.TB, path string) {
+	t.Helper()
+	if len(s.RequestsTo(path)) == 0 {
+		t.Errorf("wukongtest: expected a request to %s, got none", path)
+	}
+}
+
+// AssertNotCalled 断言指定路径没有收到过请求
+func (s *Server) AssertNotCalled(t testing.TB, path string) {
+	t.Helper()
+	if n := len(s.RequestsTo(path)); n != 0 {
+		t.Errorf("wukongtest: expected no request to %s, got %d", path, n)
+	}
+}
+
+// AssertCallCount 断言指定路径收到的请求次数
+func (s *Server) AssertCallCount(t testing.TB, path string, want int) {
+	t.Helper()
+	if n := len(s.RequestsTo(path)); n != want {
+		t.Errorf("wukongtest: expected %d requests to %s, got %d", want, path, n)
+	}
+}
+
+// AssertLastRequestBody 断言指定路径最后一个请求的请求体与 want 序列化后的 JSON 等价
+func (s *Server) AssertLastRequestBody(t testing.TB, path string, want any) {
+	t.Helper()
+
+	r, ok := s.LastRequest(path)
+	if !ok {
+		t.Errorf("wukongtest: expected a request to %s, got none", path)
+		return
+	}
+
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("wukongtest: marshal expected body: %v", err)
+	}
+
+	var got, exp any
+	if err := json.Unmarshal(r.Body, &got); err != nil {
+		t.Errorf("wukongtest: request body of %s is not JSON: %v", path, err)
+		return
+	}
+	_ = json.Unmarshal(wantJSON, &exp)
+
+	gotJSON, _ := json.Marshal(got)
+	expJSON, _ := json.Marshal(exp)
+	if string(gotJSON) != string(expJSON) {
+		t.Errorf("wukongtest: request body of %s\n got: %s\nwant: %s", path, gotJSON, expJSON)
+	}
+}
@@ -0,0 +1,224 @@
+// Package wukongtest 提供一个内存版的 WuKongIM 假服务端，用于在不依赖真实服务的情况下测试
+//
+// Server 基于 httptest.Server，实现了 SDK 调用的所有接口，并在内存中维护频道、订阅者、
+// 黑白名单、消息序号、会话未读数、在线状态与系统用户等状态；
+// 同时支持故障注入（延迟、错误率、指定状态码、断开连接）以及记录请求以便断言
+//
+//	srv := wukongtest.NewServer()
+//	defer srv.Close()
+//
+//	cli := srv.Client()
+//	_, _ = cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1", "u2"}})
+//	_, _ = cli.Message.SendMessage(ctx, &wukong.SendMessageRequest{FromUID: "u1", ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Payload: "aGk="})
+//
+//	srv.AssertCallCount(t, "/message/send", 1)
+//	if n := srv.Unread("u2", "g1", wukong.ChannelTypeGroup); n != 1 { ... }
+package wukongtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// Option 配置假服务端
+type Option func(*Server)
+
+// WithToken 要求请求携带 "Authorization: Bearer <token>"，否则返回 401
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithRouteAddress 设置 /route 与 /route/batch 返回的地址
+func WithRouteAddress(addr wukong.RouteAddress) Option {
+	return func(s *Server) {
+		s.route = addr
+	}
+}
+
+// WithManager 设置 /manager/login 接受的管理员账号，默认 admin / admin
+func WithManager(username, password string) Option {
+	return func(s *Server) {
+		s.managerUser = username
+		s.managerPass = password
+	}
+}
+
+// WithClock 替换服务端使用的时钟，用于生成消息时间戳与会话版本
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Server 内存版 WuKongIM 服务端
+type Server struct {
+	// URL 服务地址，例如 http://127.0.0.1:12345
+	URL string
+
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	token       string
+	route       wukong.RouteAddress
+	managerUser string
+	managerPass string
+	now         func() time.Time
+
+	state    *state
+	faults   faults
+	requests recorder
+}
+
+// NewServer 启动假服务端，使用完毕后需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		route: wukong.RouteAddress{
+			TCPAddr: "127.0.0.1:5100",
+			WSAddr:  "ws://127.0.0.1:5200",
+			WSSAddr: "wss://127.0.0.1:5210",
+		},
+		managerUser: "admin",
+		managerPass: "admin",
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.state = newState()
+
+	s.mux = http.NewServeMux()
+	s.routes()
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close 关闭服务端
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client 返回指向该服务端的 SDK 客户端，cfg 中的 BaseURL 会被覆盖
+// 开启了 WithToken 时会自动带上 Token
+func (s *Server) Client(cfg ...wukong.Config) *wukong.Client {
+	var c wukong.Config
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	c.BaseURL = s.URL
+	c.Endpoints = nil
+	if c.Token == "" {
+		c.Token = s.token
+	}
+	return wukong.NewClient(c)
+}
+
+// Reset 清空所有状态、故障与请求记录
+func (s *Server) Reset() {
+	s.state.reset()
+	s.faults.clear()
+	s.requests.reset()
+}
+
+// serveHTTP 记录请求、校验认证、应用故障后交给路由处理
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.requests.record(r, body, s.now())
+
+	if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if s.faults.apply(w, r) {
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// routes 注册所有接口
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+
+	s.mux.HandleFunc("GET /route", s.handleRoute)
+	s.mux.HandleFunc("POST /route/batch", s.handleRouteBatch)
+
+	s.mux.HandleFunc("POST /message/send", s.handleMessageSend)
+	s.mux.HandleFunc("POST /message/sendbatch", s.handleMessageSendBatch)
+	s.mux.HandleFunc("POST /channel/messagesync", s.handleMessageSync)
+	s.mux.HandleFunc("GET /channel/max_message_seq", s.handleMaxMessageSeq)
+	s.mux.HandleFunc("POST /plugins/wk.plugin.search/usersearch", s.handleUserSearch)
+	s.mux.HandleFunc("POST /messages", s.handleBatchSearch)
+	s.mux.HandleFunc("POST /message", s.handleSingleSearch)
+
+	s.mux.HandleFunc("POST /channel", s.handleChannelCreate)
+	s.mux.HandleFunc("POST /channel/info", s.handleChannelInfo)
+	s.mux.HandleFunc("POST /channel/delete", s.handleChannelDelete)
+	s.mux.HandleFunc("POST /channel/subscriber_add", s.handleSubscriberAdd)
+	s.mux.HandleFunc("POST /channel/subscriber_remove", s.handleSubscriberRemove)
+	s.mux.HandleFunc("POST /channel/tmp_subscriber_set", s.handleTmpSubscriberSet)
+	s.mux.HandleFunc("POST /channel/blacklist_add", s.handleUIDList(listBlacklist, opAdd))
+	s.mux.HandleFunc("POST /channel/blacklist_set", s.handleUIDList(listBlacklist, opSet))
+	s.mux.HandleFunc("POST /channel/blacklist_remove", s.handleUIDList(listBlacklist, opRemove))
+	s.mux.HandleFunc("POST /channel/whitelist_add", s.handleUIDList(listWhitelist, opAdd))
+	s.mux.HandleFunc("POST /channel/whitelist_set", s.handleUIDList(listWhitelist, opSet))
+	s.mux.HandleFunc("POST /channel/whitelist_remove", s.handleUIDList(listWhitelist, opRemove))
+	s.mux.HandleFunc("GET /channel/whitelist", s.handleWhitelistGet)
+
+	s.mux.HandleFunc("POST /user/token", s.handleUserToken)
+	s.mux.HandleFunc("POST /user/device_quit", s.handleDeviceQuit)
+	s.mux.HandleFunc("POST /user/onlinestatus", s.handleOnlineStatus)
+	s.mux.HandleFunc("GET /user/systemuids", s.handleSystemUIDs)
+	s.mux.HandleFunc("POST /user/systemuids_add", s.handleSystemUIDsChange(true))
+	s.mux.HandleFunc("POST /user/systemuids_remove", s.handleSystemUIDsChange(false))
+
+	s.mux.HandleFunc("POST /conversation/sync", s.handleConversationSync)
+	s.mux.HandleFunc("POST /conversations/clearUnread", s.handleClearUnread)
+	s.mux.HandleFunc("POST /conversations/setUnread", s.handleSetUnread)
+	s.mux.HandleFunc("POST /conversations/delete", s.handleConversationDelete)
+
+	s.mux.HandleFunc("POST /conn/remove", s.handleConnection)
+	s.mux.HandleFunc("POST /conn/kick", s.handleConnection)
+
+	s.mux.HandleFunc("POST /event", s.handleEvent)
+
+	s.mux.HandleFunc("POST /manager/login", s.handleManagerLogin)
+}
+
+// writeOK 写入通用的成功响应
+func writeOK(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, wukong.CreateChannelResponse{Status: "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError 按 WuKongIM 的格式写入错误响应：{"msg": "...", "status": 400}
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]any{"msg": msg, "status": code})
+}
+
+// decode 解析请求体，失败时写入 400 并返回 false
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package wukongtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+	"github.com/linabellbiu/wukong-go-sdk/wukongtest"
+)
+
+func groupMessage(clientMsgNo string) wukong.SendMessageRequest {
+	return wukong.SendMessageRequest{
+		ClientMsgNo: clientMsgNo,
+		FromUID:     "u1",
+		ChannelID:   "g1",
+		ChannelType: wukong.ChannelTypeGroup,
+		Payload:     "aGk=",
+	}
+}
+
+func TestSendDedupesClientMsgNo(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	if _, err := cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1", "u2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m1 := groupMessage("m1")
+	first, err := cli.Message.SendMessage(ctx, &m1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := cli.Message.SendMessage(ctx, &m1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *again != *first {
+		t.Errorf("resend = %+v, want the original %+v", again, first)
+	}
+
+	items, err := cli.Message.BatchSendMessage(ctx, &wukong.BatchSendMessageRequest{
+		Messages: []wukong.SendMessageRequest{groupMessage("m1"), groupMessage("m2"), groupMessage("m2"), groupMessage("")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].MessageID != first.MessageID || items[0].MessageSeq != first.MessageSeq {
+		t.Errorf("batch resend of m1 = %+v, want %+v", items[0], first)
+	}
+	if items[1] != items[2] {
+		t.Errorf("duplicate m2 in one batch got %+v and %+v", items[1], items[2])
+	}
+
+	msgs := srv.Messages("g1", wukong.ChannelTypeGroup)
+	if len(msgs) != 3 {
+		t.Fatalf("stored %d messages, want 3 (m1, m2 and one without client_msg_no)", len(msgs))
+	}
+	if n := srv.Unread("u2", "g1", wukong.ChannelTypeGroup); n != 3 {
+		t.Errorf("unread = %d, want 3", n)
+	}
+
+	// 不同频道中的相同 ClientMsgNo 互不影响
+	other := wukong.SendMessageRequest{ClientMsgNo: "m1", FromUID: "u1", ChannelID: "u2", ChannelType: wukong.ChannelTypePerson, Payload: "aGk="}
+	resp, err := cli.Message.SendMessage(ctx, &other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.MessageID == first.MessageID {
+		t.Error("client_msg_no was deduplicated across channels")
+	}
+}
+
+func TestPersonChannelStorage(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	for _, from := range []string{"u1", "u2", "u1"} {
+		to := map[string]string{"u1": "u2", "u2": "u1"}[from]
+		req := &wukong.SendMessageRequest{FromUID: from, ChannelID: to, ChannelType: wukong.ChannelTypePerson, Payload: "aGk="}
+		if _, err := cli.Message.SendMessage(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := srv.MaxMessageSeq(wukongtest.PersonChannelID("u2", "u1"), wukong.ChannelTypePerson); n != 3 {
+		t.Errorf("max seq = %d, want 3", n)
+	}
+	msgs, err := cli.Message.MessageSync(ctx, &wukong.MessageSyncRequest{LoginUID: "u2", ChannelID: "u1", ChannelType: wukong.ChannelTypePerson, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Errorf("u2 sees %d messages with u1, want 3", len(msgs))
+	}
+	if n := srv.Unread("u2", "u1", wukong.ChannelTypePerson); n != 2 {
+		t.Errorf("u2 unread = %d, want 2", n)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	srv := wukongtest.NewServer()
+	defer srv.Close()
+	cli := srv.Client()
+	ctx := context.Background()
+
+	msg := groupMessage("")
+	if _, err := cli.Message.SendMessage(ctx, &msg); !errors.Is(err, wukong.ErrNotFound) {
+		t.Errorf("send to missing channel: err = %v, want ErrNotFound", err)
+	}
+
+	_, _ = cli.Channel.Create(ctx, &wukong.CreateChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, Subscribers: []string{"u1"}})
+	_, _ = cli.Channel.AddBlacklist(ctx, &wukong.ChannelUIDsRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup, UIDs: []string{"u1"}})
+	if _, err := cli.Message.SendMessage(ctx, &msg); !errors.Is(err, wukong.ErrForbidden) {
+		t.Errorf("send from blacklisted uid: err = %v, want ErrForbidden", err)
+	}
+
+	msg.Payload = "not base64!"
+	if _, err := cli.Message.SendMessage(ctx, &msg); !errors.Is(err, wukong.ErrBadRequest) {
+		t.Errorf("send invalid payload: err = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestTokenAndFaults(t *testing.T) {
+	srv := wukongtest.NewServer(wukongtest.WithToken("secret"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	anonymous := wukong.NewClient(wukong.Config{BaseURL: srv.URL})
+	if _, err := anonymous.System.Health(ctx); !errors.Is(err, wukong.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+
+	cli := srv.Client()
+	srv.InjectFault(wukongtest.Fault{Path: "/health", Status: http.StatusServiceUnavailable, Times: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := cli.System.Health(ctx); !errors.Is(err, wukong.ErrServerUnavailable) {
+			t.Errorf("call %d: err = %v, want ErrServerUnavailable", i, err)
+		}
+	}
+	if _, err := cli.System.Health(ctx); err != nil {
+		t.Errorf("fault should be used up: %v", err)
+	}
+	srv.AssertCallCount(t, "/health", 4)
+
+	// 使用 POST，避免 net/http 在连接复用时自动重试幂等的 GET
+	srv.InjectFault(wukongtest.Fault{Path: "/channel/delete", Drop: true, Times: 1})
+	var te *wukong.TransportError
+	if _, err := cli.Channel.Delete(ctx, &wukong.DeleteChannelRequest{ChannelID: "g1", ChannelType: wukong.ChannelTypeGroup}); !errors.As(err, &te) {
+		t.Errorf("dropped connection: err = %v, want a TransportError", err)
+	}
+}
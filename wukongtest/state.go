@@ -0,0 +1,273 @@
+package wukongtest
+
+import (
+	"slices"
+	"sync"
+
+	wukong "github.com/linabellbiu/wukong-go-sdk"
+)
+
+// channelKey 唯一标识一个频道
+type channelKey struct {
+	id  string
+	typ wukong.ChannelType
+}
+
+// PersonChannelID 返回两个用户之间单聊消息的存储频道 ID，与 UID 的先后顺序无关
+// 单聊消息在服务端按双方共享存储，Server.Messages 查询单聊消息时使用该 ID
+func PersonChannelID(uid1, uid2 string) string {
+	if uid1 > uid2 {
+		uid1, uid2 = uid2, uid1
+	}
+	return uid1 + "@" + uid2
+}
+
+// uidSet 保持插入顺序的 UID 集合
+type uidSet []string
+
+func (s uidSet) contains(uid string) bool {
+	return slices.Contains(s, uid)
+}
+
+func (s uidSet) add(uids ...string) uidSet {
+	for _, uid := range uids {
+		if uid != "" && !s.contains(uid) {
+			s = append(s, uid)
+		}
+	}
+	return s
+}
+
+func (s uidSet) remove(uids ...string) uidSet {
+	return slices.DeleteFunc(s, func(uid string) bool {
+		return slices.Contains(uids, uid)
+	})
+}
+
+func (s uidSet) clone() []string {
+	return append([]string{}, s...)
+}
+
+// channel 频道的配置
+type channel struct {
+	large          int
+	ban            int
+	subscribers    uidSet
+	tmpSubscribers uidSet
+	blacklist      uidSet
+	whitelist      uidSet
+}
+
+// conversation 某个用户视角下的会话
+type conversation struct {
+	unread     int
+	lastMsgSeq int64
+	timestamp  int64
+	version    int64
+}
+
+// state 服务端的全部内存状态，所有字段都由 mu 保护
+type state struct {
+	mu sync.Mutex
+
+	channels map[channelKey]*channel
+	// messages 按存储频道保存的消息，按 MessageSeq 升序
+	messages      map[channelKey][]wukong.Message
+	messagesByID  map[int64]wukong.Message
+	nextMessageID int64
+	// sent 已发送消息的响应，按存储频道与 ClientMsgNo 去重
+	sent map[sentKey]wukong.SendMessageResponse
+
+	// conversations uid -> 该用户视角的频道 -> 会话
+	conversations map[string]map[channelKey]*conversation
+	version       int64
+
+	// online uid -> 设备标识 -> 是否在线
+	online map[string]map[int]bool
+	// tokens uid -> 设备标识 -> Token
+	tokens     map[string]map[int]string
+	systemUIDs uidSet
+	events     []wukong.EventSendRequest
+}
+
+func newState() *state {
+	st := &state{}
+	st.reset()
+	return st
+}
+
+func (st *state) reset() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.channels = map[channelKey]*channel{}
+	st.messages = map[channelKey][]wukong.Message{}
+	st.messagesByID = map[int64]wukong.Message{}
+	st.nextMessageID = 0
+	st.sent = map[sentKey]wukong.SendMessageResponse{}
+	st.conversations = map[string]map[channelKey]*conversation{}
+	st.version = 0
+	st.online = map[string]map[int]bool{}
+	st.tokens = map[string]map[int]string{}
+	st.systemUIDs = nil
+	st.events = nil
+}
+
+// channelOrCreate 返回频道配置，不存在时创建，调用方需持有锁
+func (st *state) channelOrCreate(key channelKey) *channel {
+	ch, ok := st.channels[key]
+	if !ok {
+		ch = &channel{}
+		st.channels[key] = ch
+	}
+	return ch
+}
+
+// storageKey 返回消息实际存储的频道，单聊消息按双方共享存储
+func storageKey(uid string, key channelKey) channelKey {
+	if key.typ == wukong.ChannelTypePerson && uid != "" {
+		return channelKey{id: PersonChannelID(uid, key.id), typ: key.typ}
+	}
+	return key
+}
+
+// sentKey 消息去重的键，与 WuKongIM 一样在同一频道内按 ClientMsgNo 去重
+type sentKey struct {
+	channel     channelKey
+	clientMsgNo string
+}
+
+// conversationOf 返回用户视角的会话，不存在时创建，调用方需持有锁
+func (st *state) conversationOf(uid string, key channelKey) *conversation {
+	convs, ok := st.conversations[uid]
+	if !ok {
+		convs = map[channelKey]*conversation{}
+		st.conversations[uid] = convs
+	}
+	conv, ok := convs[key]
+	if !ok {
+		conv = &conversation{}
+		convs[key] = conv
+	}
+	return conv
+}
+
+func (st *state) nextVersion() int64 {
+	st.version++
+	return st.version
+}
+
+func (st *state) isSystemUID(uid string) bool {
+	return st.systemUIDs.contains(uid)
+}
+
+// Messages 返回频道中已存储的消息，按 MessageSeq 升序
+// 单聊消息使用 PersonChannelID(uid1, uid2) 作为 channelID
+func (s *Server) Messages(channelID string, channelType wukong.ChannelType) []wukong.Message {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return slices.Clone(s.state.messages[channelKey{channelID, channelType}])
+}
+
+// MaxMessageSeq 返回频道当前的最大消息序号
+// 单聊消息使用 PersonChannelID(uid1, uid2) 作为 channelID
+func (s *Server) MaxMessageSeq(channelID string, channelType wukong.ChannelType) int64 {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return maxSeq(s.state.messages[channelKey{channelID, channelType}])
+}
+
+// ChannelExists 判断频道是否存在
+func (s *Server) ChannelExists(channelID string, channelType wukong.ChannelType) bool {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	_, ok := s.state.channels[channelKey{channelID, channelType}]
+	return ok
+}
+
+// Subscribers 返回频道的订阅者
+func (s *Server) Subscribers(channelID string, channelType wukong.ChannelType) []string {
+	return s.channelList(channelID, channelType, func(ch *channel) uidSet { return ch.subscribers })
+}
+
+// TmpSubscribers 返回频道的临时订阅者
+func (s *Server) TmpSubscribers(channelID string, channelType wukong.ChannelType) []string {
+	return s.channelList(channelID, channelType, func(ch *channel) uidSet { return ch.tmpSubscribers })
+}
+
+// Blacklist 返回频道的黑名单
+func (s *Server) Blacklist(channelID string, channelType wukong.ChannelType) []string {
+	return s.channelList(channelID, channelType, func(ch *channel) uidSet { return ch.blacklist })
+}
+
+// Whitelist 返回频道的白名单
+func (s *Server) Whitelist(channelID string, channelType wukong.ChannelType) []string {
+	return s.channelList(channelID, channelType, func(ch *channel) uidSet { return ch.whitelist })
+}
+
+func (s *Server) channelList(channelID string, channelType wukong.ChannelType, list func(*channel) uidSet) []string {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	ch, ok := s.state.channels[channelKey{channelID, channelType}]
+	if !ok {
+		return nil
+	}
+	return list(ch).clone()
+}
+
+// Unread 返回用户在某个会话中的未读数，单聊时 channelID 为对方 UID
+func (s *Server) Unread(uid, channelID string, channelType wukong.ChannelType) int {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	if conv, ok := s.state.conversations[uid][channelKey{channelID, channelType}]; ok {
+		return conv.unread
+	}
+	return 0
+}
+
+// SetOnline 设置用户某个设备的在线状态，/user/onlinestatus 会据此返回结果
+func (s *Server) SetOnline(uid string, deviceFlag int, online bool) {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	devices, ok := s.state.online[uid]
+	if !ok {
+		devices = map[int]bool{}
+		s.state.online[uid] = devices
+	}
+	if online {
+		devices[deviceFlag] = true
+	} else {
+		delete(devices, deviceFlag)
+	}
+}
+
+// UserToken 返回通过 /user/token 设置的 Token
+func (s *Server) UserToken(uid string, deviceFlag int) string {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.tokens[uid][deviceFlag]
+}
+
+// SystemUIDs 返回当前的系统用户
+func (s *Server) SystemUIDs() []string {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.systemUIDs.clone()
+}
+
+// Events 返回通过 /event 收到的事件
+func (s *Server) Events() []wukong.EventSendRequest {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return slices.Clone(s.state.events)
+}
+
+func maxSeq(msgs []wukong.Message) int64 {
+	if len(msgs) == 0 {
+		return 0
+	}
+	return msgs[len(msgs)-1].MessageSeq
+}